@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+func runReboot(g *globalOptions, args []string) error {
+	fs := newFlagSet("reboot", g)
+	yes := fs.Bool("yes", false, "Confirm the reboot (required)")
+	wait := fs.Duration("wait", 3*time.Minute, "Maximum time to wait for the device to come back (0 to not wait)")
+	fs.Parse(args)
+
+	if !*yes {
+		return fmt.Errorf("rebooting interrupts all traffic through the switch; confirm with -yes")
+	}
+	password, err := g.adminPassword()
+	if err != nil {
+		return err
+	}
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	g.printBanner("NSDP Reboot")
+	device, err := g.singleDevice(client)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Device: %s\n\n", describeDevice(device))
+
+	// The device may restart before it answers the write request
+	start := time.Now()
+	if err := client.Reboot(device.MAC, password); err != nil {
+		var writeErr *nsdpclient.WriteError
+		if errors.As(err, &writeErr) {
+			return err
+		}
+		fmt.Printf("No confirmation received (%v); the device may already be restarting\n", err)
+	} else {
+		fmt.Println("Reboot requested.")
+	}
+	if *wait == 0 {
+		return nil
+	}
+
+	fmt.Printf("Waiting up to %v for the device to come back...\n", *wait)
+	back, err := client.WaitForReboot(device.MAC, *wait)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Device back after %v: %s\n", time.Since(start).Round(time.Second), describeDevice(back))
+	return nil
+}
+
+func runFactoryReset(g *globalOptions, args []string) error {
+	fs := newFlagSet("factory-reset", g)
+	yes := fs.Bool("yes", false, "Confirm the factory reset (required)")
+	fs.Parse(args)
+
+	if !*yes {
+		return fmt.Errorf("a factory reset erases the whole configuration of the switch; confirm with -yes")
+	}
+	password, err := g.adminPassword()
+	if err != nil {
+		return err
+	}
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	g.printBanner("NSDP Factory Reset")
+	device, err := g.singleDevice(client)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Device: %s\n\n", describeDevice(device))
+
+	if err := client.FactoryReset(device.MAC, password); err != nil {
+		return err
+	}
+	fmt.Println("Factory reset requested. The switch restarts with its default IP settings and admin password.")
+	return nil
+}
+
+func runResetStats(g *globalOptions, args []string) error {
+	fs := newFlagSet("reset-stats", g)
+	yes := fs.Bool("yes", false, "Confirm resetting the port statistics (required)")
+	fs.Parse(args)
+
+	if !*yes {
+		return fmt.Errorf("resetting clears the port counters of the switch; confirm with -yes")
+	}
+	password, err := g.adminPassword()
+	if err != nil {
+		return err
+	}
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	g.printBanner("NSDP Port Statistics Reset")
+	device, err := g.singleDevice(client)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Device: %s\n\n", describeDevice(device))
+
+	if err := client.ResetPortStatistics(device.MAC, password); err != nil {
+		return err
+	}
+	fmt.Println("Port statistics reset.")
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+func runCableTest(g *globalOptions, args []string) error {
+	fs := newFlagSet("cable-test", g)
+	port := fs.Uint("port", 0, "Port to test (required)")
+	wait := fs.Duration("wait", 30*time.Second, "Maximum time to wait for the test result")
+	fs.Parse(args)
+
+	if *port < 1 || *port > 255 {
+		return fmt.Errorf("a port between 1 and 255 is required (-port)")
+	}
+	password, err := g.adminPassword()
+	if err != nil {
+		return err
+	}
+
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	g.printBanner("NSDP Cable Test")
+	device, err := g.singleDevice(client)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Device: %s\n", describeDevice(device))
+
+	fmt.Printf("\n--- Cable Test (Port %d) ---\n", *port)
+	result, err := client.TestCable(device.MAC, uint8(*port), password, *wait)
+	if err != nil {
+		return fmt.Errorf("cable test failed: %w", err)
+	}
+	printCableTestResult(result)
+	return nil
+}
+
+func printCableTestResult(result *nsdpclient.CableTestResult) {
+	if len(result.Pairs) == 1 {
+		fmt.Printf("Cable: %s\n", formatCablePairResult(result.Pairs[0]))
+		return
+	}
+	for i, pair := range result.Pairs {
+		fmt.Printf("Pair %d: %s\n", i+1, formatCablePairResult(pair))
+	}
+}
+
+// formatCablePairResult formats a cable test result including the fault
+// distance if the pair is faulty.
+func formatCablePairResult(pair nsdpclient.CablePairResult) string {
+	if pair.Status == nsdpclient.CableOK || pair.Status == nsdpclient.CableNoCable {
+		return pair.Status.String()
+	}
+	return fmt.Sprintf("%s (fault at %d m)", pair.Status, pair.FaultDistance)
+}
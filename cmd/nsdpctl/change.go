@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+// changeSteps describe a configuration change of one device. T holds the
+// values the change is checked against and verified with. Callbacks that
+// may be nil are marked optional.
+type changeSteps[T any] struct {
+	title string // Banner title
+	what  string // Values read, for error messages
+
+	// Reads the current values right before the change
+	read func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) (T, error)
+	// Checks the change against the current values (optional)
+	validate func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, current T) error
+	// Prints old and new values; false if there is nothing to change
+	print func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, current T) bool
+	// Writes the change
+	apply func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, password string, current T) error
+	// Reads back the values to verify the change (optional, read by default)
+	reread func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) (T, error)
+	// Returns the values the device does not report as expected
+	mismatches func(updated T) []string
+
+	note string // Printed after a verified change (optional)
+}
+
+// changeDevice runs a configuration change on the selected device: it reads
+// the current values, validates and prints the change, asks for confirmation
+// unless yes is set, writes the change and reads it back to verify it.
+func changeDevice[T any](g *globalOptions, yes bool, steps changeSteps[T]) error {
+	password, err := g.adminPassword()
+	if err != nil {
+		return err
+	}
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	g.printBanner(steps.title)
+	device, err := g.singleDevice(client)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Device: %s\n\n", describeDevice(device))
+
+	current, err := steps.read(client, device)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", steps.what, err)
+	}
+	if steps.validate != nil {
+		if err := steps.validate(client, device, current); err != nil {
+			if device.Model != "" {
+				return fmt.Errorf("%s: %w", device.Model, err)
+			}
+			return err
+		}
+	}
+	if !steps.print(client, device, current) {
+		return nil
+	}
+	if !yes && !confirm("\nApply this change?") {
+		return fmt.Errorf("aborted")
+	}
+	if err := steps.apply(client, device, password, current); err != nil {
+		return err
+	}
+
+	reread := steps.reread
+	if reread == nil {
+		reread = steps.read
+	}
+	updated, err := reread(client, device)
+	if err != nil {
+		return fmt.Errorf("change sent, but verification failed: %w", err)
+	}
+	if mismatches := steps.mismatches(updated); len(mismatches) > 0 {
+		for _, mismatch := range mismatches {
+			fmt.Printf("WARNING: %s\n", mismatch)
+		}
+		return fmt.Errorf("verification failed: the device does not report %d change(s)", len(mismatches))
+	}
+	fmt.Println("Change applied and verified.")
+	if steps.note != "" {
+		fmt.Println(steps.note)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hdecarne-github/go-nsdp"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+// Discovery response of the switch the change tests write to
+const changeTestResponse = "0102000000000000bcd07432b8dc6cb0ce1c8394000099d14e534450000000000001000847533130384576330003000773776974636831000400066cb0ce1c8394ffff0000"
+
+func TestChangeDevice(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string // Value read before the change
+		updated string // Value read back afterwards
+		written bool
+		ok      bool
+	}{
+		{"verified", "old", "new", true, true},
+		{"not reported", "old", "old", true, false},
+		{"nothing to change", "new", "new", false, true},
+	}
+	for _, tt := range tests {
+		responder, err := nsdp.NewTestResponder("127.0.0.1:63322")
+		if err != nil {
+			t.Fatalf("Failed to create test responder: %v", err)
+		}
+		responder.AddResponses(changeTestResponse)
+		if err := responder.Start(); err != nil {
+			t.Fatalf("Failed to start test responder: %v", err)
+		}
+
+		g := &globalOptions{target: "127.0.0.1", password: "password", passwordMode: "plain", output: "text", timeout: 200 * time.Millisecond}
+		values := []string{tt.current, tt.updated}
+		written := false
+		err = changeDevice(g, true, changeSteps[string]{
+			title: "Test Change",
+			what:  "test value",
+			read: func(*nsdpclient.Client, *nsdpclient.DeviceInfo) (string, error) {
+				value := values[0]
+				values = values[1:]
+				return value, nil
+			},
+			print: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, current string) bool {
+				return current != "new"
+			},
+			apply: func(*nsdpclient.Client, *nsdpclient.DeviceInfo, string, string) error {
+				written = true
+				return nil
+			},
+			mismatches: func(updated string) []string {
+				if updated != "new" {
+					return []string{"value not changed"}
+				}
+				return nil
+			},
+		})
+		responder.Stop()
+
+		if written != tt.written {
+			t.Errorf("%s: expected written %v, got %v", tt.name, tt.written, written)
+		}
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: unexpected result %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/hdecarne-github/go-nsdp"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+func runDiscover(g *globalOptions, args []string) error {
+	fs := newFlagSet("discover", g)
+	fs.Parse(args)
+
+	switch {
+	case g.output == "json":
+		return g.reportDevices(0, nsdp.EmptyPortStatus(), nsdp.EmptyVLANInfo())
+	case g.tabular():
+		return g.tabulateDevices(deviceTable, 0, nsdp.EmptyPortStatus())
+	}
+
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	g.printBanner("Netgear Switch Discovery Protocol (NSDP) Query")
+
+	// Port and VLAN status are requested on top of the identification set
+	devices, err := g.discoverDevices(client,
+		nsdp.EmptyPortStatus(), // 0x0c00 - Speed/link status of ports
+		nsdp.EmptyVLANInfo(),   // 0x2800 - VLAN information
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, device := range devices {
+		fmt.Printf("=== Device %d ===\n", i+1)
+		printDeviceInfo(device, g.verbose)
+		fmt.Println()
+	}
+	return nil
+}
+
+func runShow(g *globalOptions, args []string) error {
+	fs := newFlagSet("show", g)
+	comprehensive := fs.Bool("c", false, "Enable comprehensive parameter querying")
+	ports := fs.Bool("ports", false, "With -o csv or table, write one row per port instead of per device")
+	fs.Parse(args)
+
+	if *ports && !g.tabular() {
+		return fmt.Errorf("-ports requires -o csv or -o table")
+	}
+	switch {
+	case g.output == "json":
+		if *comprehensive {
+			return g.reportDevices(sectionAll)
+		}
+		return g.reportDevices(sectionPorts)
+	case *ports:
+		return g.tabulateDevices(portStatusTable, sectionPorts)
+	case g.tabular():
+		return g.tabulateDevices(deviceTable, sectionPorts)
+	}
+
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	g.printBanner("Enhanced Netgear Switch Discovery Protocol (NSDP) Query")
+	fmt.Printf("Comprehensive Mode: %v\n\n", *comprehensive)
+
+	devices, err := g.discoverDevices(client)
+	if err != nil {
+		return err
+	}
+
+	for i, device := range devices {
+		fmt.Printf("=== Device %d ===\n", i+1)
+		printDeviceInfo(device, g.verbose)
+
+		// Query comprehensive device details if requested
+		if *comprehensive {
+			queryComprehensiveDeviceDetails(client, device, g.verbose)
+		} else {
+			// Query basic additional information
+			queryBasicDeviceDetails(client, device, g.verbose)
+		}
+
+		fmt.Println()
+	}
+	return nil
+}
+
+func printDeviceInfo(info *nsdpclient.DeviceInfo, verbose bool) {
+	fmt.Println("--- Device Identification ---")
+
+	if verbose {
+		for _, tlv := range info.Unknown {
+			fmt.Printf("Unknown TLV type: %T\n", tlv)
+		}
+	}
+
+	// Display device identification
+	if info.MAC != nil {
+		fmt.Printf("Device MAC: %s\n", info.MAC)
+	}
+	if info.Interface != "" {
+		fmt.Printf("Interface: %s\n", info.Interface)
+	}
+	if info.Model != "" {
+		fmt.Printf("Model: %s\n", info.Model)
+	}
+	if info.Name != "" {
+		fmt.Printf("Device Name: %s\n", info.Name)
+	}
+	if info.Location != "" {
+		fmt.Printf("Location: %s\n", info.Location)
+	}
+
+	// Display network configuration
+	if info.IP != nil || info.Netmask != nil || info.Gateway != nil || info.DHCPMode != nil {
+		fmt.Println("\n--- Network Configuration ---")
+		if info.IP != nil {
+			fmt.Printf("IP Address: %s\n", info.IP)
+		}
+		if info.Netmask != nil {
+			fmt.Printf("Subnet Mask: %s\n", info.Netmask)
+		}
+		if info.Gateway != nil {
+			fmt.Printf("Gateway: %s\n", info.Gateway)
+		}
+		if info.DHCPMode != nil {
+			fmt.Printf("DHCP: %s\n", formatDHCPMode(*info.DHCPMode))
+		}
+	}
+
+	// Display firmware information
+	if info.FWVersionSlot1 != "" || info.FWVersionSlot2 != "" || info.NextFWSlot != 0 {
+		fmt.Println("\n--- Firmware Information ---")
+		if info.FWVersionSlot1 != "" {
+			fmt.Printf("Firmware Version (Slot 1): %s\n", info.FWVersionSlot1)
+		}
+		if info.FWVersionSlot2 != "" {
+			fmt.Printf("Firmware Version (Slot 2): %s\n", info.FWVersionSlot2)
+		}
+		if info.NextFWSlot != 0 {
+			fmt.Printf("Next Boot Slot: Slot %d\n", info.NextFWSlot)
+		}
+	}
+
+	// Display port status information
+	if len(info.Ports) > 0 {
+		fmt.Println("\n--- Port Status ---")
+		for _, ps := range info.Ports {
+			fmt.Printf("Port %d: %s\n", ps.Port, formatPortStatus(ps))
+		}
+	}
+
+	// Display VLAN information
+	if len(info.VLANs) > 0 {
+		fmt.Println("\n--- VLAN Configuration ---")
+		for _, vi := range info.VLANs {
+			fmt.Printf("VLAN %d: %s\n", vi.VLANID, formatVLANInfo(vi))
+		}
+	}
+}
+
+// Helper function to format port status information
+func formatPortStatus(ps *nsdp.PortStatus) string {
+	status := "Down"
+	if ps.LinkUp {
+		status = fmt.Sprintf("Up (%d Mbps, %s)", ps.Speed, ps.Duplex)
+	}
+	return status
+}
+
+// Helper function to format VLAN information
+func formatVLANInfo(vi *nsdp.VLANInfo) string {
+	return fmt.Sprintf("Tagged: %v, Untagged: %v", vi.TaggedPorts, vi.UntaggedPorts)
+}
+
+func queryBasicDeviceDetails(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, verbose bool) {
+	if device.MAC == nil {
+		if verbose {
+			fmt.Println("Cannot query device details: no MAC address found")
+		}
+		return
+	}
+
+	// Query basic port information
+	if report := queryPortReport(client, device.MAC, verbose); report != nil {
+		printPortStatusReport(report, verbose)
+		printAvailablePorts(report)
+	}
+}
+
+func queryComprehensiveDeviceDetails(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, verbose bool) {
+	if device.MAC == nil {
+		if verbose {
+			fmt.Println("Cannot query device details: no MAC address found")
+		}
+		return
+	}
+
+	deviceMAC := device.MAC
+	fmt.Println("--- Comprehensive Device Analysis ---")
+
+	// Query all available parameters systematically
+	if report := queryPortReport(client, deviceMAC, verbose); report != nil {
+		printAvailablePorts(report)
+		printPortStatusReport(report, verbose)
+		fmt.Println("\n--- Port Statistics ---")
+		printPortStatisticsReport(report, verbose)
+	}
+	queryVLANConfiguration(client, deviceMAC, verbose)
+	queryQoSConfiguration(client, deviceMAC, verbose)
+	queryIGMPConfiguration(client, deviceMAC, verbose)
+	queryPortMirroring(client, deviceMAC, verbose)
+	queryLoopDetection(client, deviceMAC, verbose)
+	queryUnknownParameters(client, deviceMAC, verbose)
+}
+
+// queryPortReport reads the status and counters of all ports of a device
+// with a single request.
+func queryPortReport(client *nsdpclient.Client, deviceMAC net.HardwareAddr, verbose bool) *nsdpclient.PortReport {
+	if verbose {
+		fmt.Println("Querying ports...")
+	}
+
+	report, err := client.Ports(deviceMAC)
+	if err != nil {
+		if verbose {
+			fmt.Printf("Error querying ports - %v\n", err)
+		}
+		return nil
+	}
+	return report
+}
+
+func printAvailablePorts(report *nsdpclient.PortReport) {
+	if report.PortCount > 0 {
+		fmt.Printf("Available Ports: %d\n", report.PortCount)
+	}
+}
+
+// queryPortCount returns the number of ports of a device, or 0 if unknown.
+func queryPortCount(client *nsdpclient.Client, deviceMAC net.HardwareAddr, verbose bool) int {
+	result := queryCustomParameter(client, deviceMAC, nsdpclient.ParamAvailablePorts, verbose)
+	if len(result) >= 1 {
+		return int(result[0])
+	}
+	return 0
+}
+
+func printPortStatusReport(report *nsdpclient.PortReport, verbose bool) {
+	fmt.Println("\n--- Port Status ---")
+
+	for p := 1; p <= report.Count(); p++ {
+		ps := report.StatusOf(uint8(p))
+		if ps == nil {
+			if verbose {
+				fmt.Printf("Port %d: No status available\n", p)
+			}
+			continue
+		}
+		fmt.Printf("Port %d: %s\n", ps.Port, formatPortStatusByte(ps.Status))
+	}
+}
+
+func queryLoopDetection(client *nsdpclient.Client, deviceMAC net.HardwareAddr, verbose bool) {
+	fmt.Println("\n--- Loop Detection ---")
+
+	result := queryCustomParameter(client, deviceMAC, nsdpclient.ParamLoopDetection, verbose)
+	if result != nil && len(result) >= 1 {
+		enabled := result[0]
+		fmt.Printf("Loop Detection: %s\n", formatEnabledDisabled(enabled))
+	}
+}
+
+func queryUnknownParameters(client *nsdpclient.Client, deviceMAC net.HardwareAddr, verbose bool) {
+	if !verbose {
+		return
+	}
+
+	fmt.Println("\n--- Unknown Parameters ---")
+
+	// Query unknown parameters for research purposes
+	unknownParams := []uint16{nsdpclient.ParamUnknown8C00, nsdpclient.ParamVLANUnknown}
+
+	for _, param := range unknownParams {
+		result := queryCustomParameter(client, deviceMAC, param, verbose)
+		if result != nil {
+			fmt.Printf("Parameter 0x%04x: %d bytes - %x\n", param, len(result), result)
+		}
+	}
+}
+
+// Generic function to query custom parameters
+func queryCustomParameter(client *nsdpclient.Client, deviceMAC net.HardwareAddr, paramType uint16, verbose bool) []byte {
+	result, err := client.ReadParam(deviceMAC, paramType)
+	if err != nil {
+		if verbose {
+			fmt.Printf("Error querying parameter 0x%04x: %v\n", paramType, err)
+		}
+		return nil
+	}
+	return result
+}
+
+// Print the raw records of a parameter in verbose mode
+func printRawRecords(records [][]byte, verbose bool) {
+	if !verbose {
+		return
+	}
+	for _, record := range records {
+		fmt.Printf("  Raw data: %x\n", record)
+	}
+}
+
+// Query a parameter reported as one record per port or VLAN
+func queryCustomParameterRecords(client *nsdpclient.Client, deviceMAC net.HardwareAddr, paramType uint16, verbose bool) [][]byte {
+	result, err := client.ReadParams(deviceMAC, paramType)
+	if err != nil {
+		if verbose {
+			fmt.Printf("Error querying parameter 0x%04x: %v\n", paramType, err)
+		}
+		return nil
+	}
+	return result.Records(paramType)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// exporterSections are the report sections read on each scrape.
+const exporterSections = sectionStatistics | sectionVLANs | sectionLoopDetection
+
+func runExporter(g *globalOptions, args []string) error {
+	fs := newFlagSet("exporter", g)
+	listen := fs.String("listen", ":9753", "Address to serve metrics on")
+	maxAge := fs.Duration("cache", 30*time.Second, "How long collected metrics are served before the switches are queried again")
+	fs.Parse(args)
+
+	g.structured = true
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	e := &exporter{
+		maxAge: *maxAge,
+		collect: func() ([]*deviceReport, error) {
+			return g.readReports(client, exporterSections)
+		},
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, `<html><body><h1>NSDP Exporter</h1><p><a href="/metrics">Metrics</a></p></body></html>`)
+	})
+
+	log.Printf("Serving NSDP metrics on %s/metrics", *listen)
+	return http.ListenAndServe(*listen, mux)
+}
+
+// exporter serves the metrics of the discovered devices. Switches are only
+// queried when a scrape finds the collected metrics older than maxAge, so
+// several Prometheus servers scraping at once cause a single NSDP round.
+type exporter struct {
+	maxAge  time.Duration
+	collect func() ([]*deviceReport, error)
+
+	mu        sync.Mutex
+	reports   []*deviceReport
+	collected time.Time
+	duration  time.Duration
+	err       error
+}
+
+func (e *exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := writeMetrics(&buf, e.metrics()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// metrics returns the metric families, collecting them again if the cached
+// ones are too old. A failed collection keeps the previous devices and is
+// reported with nsdp_scrape_success.
+func (e *exporter) metrics() []*metricFamily {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.collected.IsZero() || time.Since(e.collected) >= e.maxAge {
+		start := time.Now()
+		reports, err := e.collect()
+		e.duration = time.Since(start)
+		e.collected = start
+		e.err = err
+		if err != nil {
+			log.Printf("Collecting metrics failed: %v", err)
+		} else {
+			e.reports = reports
+		}
+	}
+
+	success := &metricFamily{name: "nsdp_scrape_success", help: "Whether the last NSDP collection succeeded.", kind: "gauge"}
+	success.add(boolValue(e.err == nil))
+	duration := &metricFamily{name: "nsdp_scrape_duration_seconds", help: "Duration of the last NSDP collection.", kind: "gauge"}
+	duration.add(e.duration.Seconds())
+	timestamp := &metricFamily{name: "nsdp_scrape_timestamp_seconds", help: "Time of the last NSDP collection as a Unix timestamp.", kind: "gauge"}
+	timestamp.add(float64(e.collected.Unix()))
+	devices := &metricFamily{name: "nsdp_devices", help: "Number of devices answering discovery.", kind: "gauge"}
+	devices.add(float64(len(e.reports)))
+
+	return append([]*metricFamily{success, duration, timestamp, devices}, deviceMetrics(e.reports)...)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeviceMetrics(t *testing.T) {
+	enabled := true
+	reports := testReports()
+	reports[0].VLANs = &vlanReport{Memberships: []vlanMembershipReport{{ID: 1}, {ID: 10}}}
+	reports[0].LoopDetection = &enabled
+	reports[1].Identity.Name = `lab "rack" 2`
+
+	var buf strings.Builder
+	if err := writeMetrics(&buf, deviceMetrics(reports)); err != nil {
+		t.Fatalf("writeMetrics failed: %v", err)
+	}
+	metrics := buf.String()
+	for _, expected := range []string{
+		"# TYPE nsdp_port_receive_bytes_total counter\n",
+		`nsdp_device_info{mac="6c:b0:ce:1c:83:94",name="switch1",model="GS108Ev3",ip="10.1.0.3",boot_firmware="2.06.17"} 1`,
+		`nsdp_device_info{mac="00:11:22:33:44:55",name="lab \"rack\" 2",model="GS105E",ip="",boot_firmware=""} 1`,
+		`nsdp_firmware_info{mac="6c:b0:ce:1c:83:94",name="switch1",slot="2",version="2.06.10",next_boot="false"} 1`,
+		`nsdp_vlans{mac="6c:b0:ce:1c:83:94",name="switch1"} 2`,
+		`nsdp_loop_detection_enabled{mac="6c:b0:ce:1c:83:94",name="switch1"} 1`,
+		`nsdp_port_up{mac="6c:b0:ce:1c:83:94",name="switch1",port="2"} 0`,
+		`nsdp_port_speed_mbps{mac="6c:b0:ce:1c:83:94",name="switch1",port="1"} 1000`,
+		`nsdp_port_receive_bytes_total{mac="6c:b0:ce:1c:83:94",name="switch1",port="1"} 1024`,
+		`nsdp_port_errors_total{mac="6c:b0:ce:1c:83:94",name="switch1",port="1"} 2`,
+	} {
+		if !strings.Contains(metrics, expected) {
+			t.Errorf("Missing %q in:\n%s", expected, metrics)
+		}
+	}
+	if strings.Contains(metrics, `nsdp_port_receive_bytes_total{mac="6c:b0:ce:1c:83:94",name="switch1",port="2"}`) {
+		t.Error("Expected no counters for a port without statistics")
+	}
+}
+
+func TestExporterCache(t *testing.T) {
+	collections := 0
+	var err error
+	e := &exporter{
+		maxAge: time.Hour,
+		collect: func() ([]*deviceReport, error) {
+			collections++
+			return testReports(), err
+		},
+	}
+	scrape := func() string {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+		body, _ := io.ReadAll(w.Result().Body)
+		return string(body)
+	}
+
+	first := scrape()
+	if !strings.Contains(first, "nsdp_devices 2\n") || !strings.Contains(first, "nsdp_scrape_success 1\n") {
+		t.Errorf("Unexpected metrics:\n%s", first)
+	}
+	scrape()
+	if collections != 1 {
+		t.Errorf("Expected cached metrics, got %d collections", collections)
+	}
+
+	// Failed collections keep the previous devices
+	e.maxAge = 0
+	err = errors.New("timeout")
+	failed := scrape()
+	if collections != 2 || !strings.Contains(failed, "nsdp_scrape_success 0\n") || !strings.Contains(failed, "nsdp_devices 2\n") {
+		t.Errorf("Unexpected metrics after failure (%d collections):\n%s", collections, failed)
+	}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"nsdp/pkg/nsdpclient"
+	"nsdp/pkg/tftp"
+)
+
+func runFirmware(g *globalOptions, args []string) error {
+	// Global flags may precede the subcommand
+	fs := newFlagSet("firmware", g)
+	fleet := fs.Bool("fleet", false, "Group the switches by model and next boot firmware version")
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		switch fs.Arg(0) {
+		case "boot":
+			return runFirmwareBoot(g, fs.Args()[1:])
+		case "upgrade":
+			return runFirmwareUpgrade(g, fs.Args()[1:])
+		}
+		return fmt.Errorf("unknown subcommand %q (boot or upgrade)", fs.Arg(0))
+	}
+	if g.output == "json" {
+		if *fleet {
+			return fmt.Errorf("-fleet is only available with -o text")
+		}
+		return g.reportDevices(0)
+	}
+
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	g.printBanner("NSDP Firmware Inventory")
+	devices, err := g.discoverDevices(client)
+	if err != nil || len(devices) == 0 {
+		return err
+	}
+
+	if *fleet {
+		printFirmwareGroups(nsdpclient.GroupByFirmware(devices))
+		return nil
+	}
+	for i, device := range devices {
+		fmt.Printf("=== Device %d ===\n", i+1)
+		fmt.Printf("Device: %s\n", describeDevice(device))
+		printFirmwareSlots(nsdpclient.FirmwareOf(device))
+		fmt.Println()
+	}
+	return nil
+}
+
+// printFirmwareSlots prints the firmware version of each slot along with the
+// one the device boots from next. The running slot is not reported by NSDP.
+func printFirmwareSlots(firmware nsdpclient.FirmwareInfo) {
+	if !firmware.DualImage() {
+		fmt.Printf("Firmware: %s (single image)\n", firmware.Version(1))
+		return
+	}
+	for slot := uint8(1); slot <= 2; slot++ {
+		version := firmware.Version(slot)
+		if version == "" {
+			version = "(empty)"
+		}
+		if slot == firmware.NextSlot {
+			version += " (next boot)"
+		}
+		fmt.Printf("Slot %d: %s\n", slot, version)
+	}
+}
+
+// printFirmwareGroups prints one line per model and next boot firmware
+// version with the switches booting it.
+func printFirmwareGroups(groups []nsdpclient.FirmwareGroup) {
+	fmt.Printf("%-16s %-12s %-8s %s\n", "Model", "Next Boot", "Switches", "Names")
+	for _, group := range groups {
+		names := make([]string, len(group.Devices))
+		for i, device := range group.Devices {
+			names[i] = device.Name
+			if names[i] == "" {
+				names[i] = device.MAC.String()
+			}
+		}
+		model, version := group.Model, group.Version
+		if model == "" {
+			model = "(unknown)"
+		}
+		if version == "" {
+			version = "(unknown)"
+		}
+		fmt.Printf("%-16s %-12s %-8d %s\n", model, version, len(group.Devices), strings.Join(names, ", "))
+	}
+}
+
+func runFirmwareBoot(g *globalOptions, args []string) error {
+	fs := newFlagSet("firmware boot", g)
+	slot := fs.Uint("slot", 0, "Firmware slot to boot from next: 1 or 2 (required)")
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+	fs.Parse(args)
+
+	if *slot != 1 && *slot != 2 {
+		return fmt.Errorf("a firmware slot of 1 or 2 is required (-slot)")
+	}
+	nextSlot := uint8(*slot)
+
+	return changeDevice(g, *yes, changeSteps[nsdpclient.FirmwareInfo]{
+		title: "NSDP Firmware Boot Slot Change",
+		what:  "firmware slots",
+		read: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) (nsdpclient.FirmwareInfo, error) {
+			info, err := client.DeviceInfo(device.MAC)
+			if err != nil {
+				return nsdpclient.FirmwareInfo{}, err
+			}
+			return nsdpclient.FirmwareOf(info), nil
+		},
+		validate: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, firmware nsdpclient.FirmwareInfo) error {
+			if !firmware.DualImage() {
+				return fmt.Errorf("the switch has a single firmware image")
+			}
+			if firmware.Version(nextSlot) == "" {
+				return fmt.Errorf("firmware slot %d is empty", nextSlot)
+			}
+			return nil
+		},
+		print: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, firmware nsdpclient.FirmwareInfo) bool {
+			printFirmwareSlots(firmware)
+			if firmware.NextSlot == nextSlot {
+				fmt.Printf("\nThe switch already boots from slot %d\n", nextSlot)
+				return false
+			}
+			fmt.Printf("\nNext Boot Slot: %d -> %d (%s)\n", firmware.NextSlot, nextSlot, firmware.Version(nextSlot))
+			return true
+		},
+		apply: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, password string, _ nsdpclient.FirmwareInfo) error {
+			return client.SetNextFirmwareSlot(device.MAC, password, nextSlot)
+		},
+		mismatches: func(firmware nsdpclient.FirmwareInfo) []string {
+			if firmware.NextSlot != nextSlot {
+				return []string{fmt.Sprintf("the switch still boots from slot %d", firmware.NextSlot)}
+			}
+			return nil
+		},
+		note: "It takes effect with the next reboot (reboot -yes).",
+	})
+}
+
+func runFirmwareUpgrade(g *globalOptions, args []string) error {
+	fs := newFlagSet("firmware upgrade", g)
+	imagePath := fs.String("image", "", "Firmware image file (required)")
+	force := fs.Bool("force", false, "Upgrade even if the image header names another model")
+	tftpPort := fs.Uint("tftp-port", 69, "Port of the embedded TFTP server")
+	wait := fs.Duration("wait", 10*time.Minute, "Maximum time to wait for the transfer and the restart")
+	yes := fs.Bool("yes", false, "Upgrade without asking for confirmation")
+	fs.Parse(args)
+
+	if *imagePath == "" {
+		return fmt.Errorf("a firmware image is required (-image)")
+	}
+	data, err := os.ReadFile(*imagePath)
+	if err != nil {
+		return err
+	}
+	image, err := nsdpclient.ParseFirmwareImage(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *imagePath, err)
+	}
+	password, err := g.adminPassword()
+	if err != nil {
+		return err
+	}
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	g.printBanner("NSDP Firmware Upgrade")
+	device, err := g.singleDevice(client)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Device: %s\n\n", describeDevice(device))
+
+	if !image.MatchesModel(device.Model) {
+		if !*force {
+			return fmt.Errorf("the image is built for %s, not %q (use -force to upgrade anyway)", image.Model, device.Model)
+		}
+		fmt.Printf("WARNING: the image is built for %s, not %q\n", image.Model, device.Model)
+	}
+	firmware := nsdpclient.FirmwareOf(device)
+	printFirmwareSlots(firmware)
+	fmt.Printf("\nImage: %s (%s %s, %d bytes)\n", filepath.Base(*imagePath), image.Model, formatImageVersion(image), len(data))
+	if slot := firmware.Slot(image.Version); slot != 0 {
+		fmt.Printf("Note: slot %d already holds firmware %s\n", slot, image.Version)
+	}
+
+	serverIP, err := tftpAddress(device)
+	if err != nil {
+		return err
+	}
+	if !*yes && !confirm("\nUpgrade the firmware? The switch restarts afterwards.") {
+		return fmt.Errorf("aborted")
+	}
+
+	name := filepath.Base(*imagePath)
+	server, err := tftp.Listen(net.JoinHostPort(serverIP.String(), strconv.FormatUint(uint64(*tftpPort), 10)), name, data)
+	if err != nil {
+		return fmt.Errorf("failed to start TFTP server: %w", err)
+	}
+	defer server.Close()
+	server.Progress = transferProgress()
+	done := make(chan error, 1)
+	go func() { done <- server.Serve() }()
+	fmt.Printf("Serving %s via TFTP on %s\n", name, server.Addr())
+
+	start := time.Now()
+	if err := client.StartFirmwareUpgrade(device.MAC, password, serverIP, name); err != nil {
+		return err
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("transfer failed: %w", err)
+		}
+	case <-time.After(*wait):
+		return fmt.Errorf("the switch did not fetch the image within %v", *wait)
+	}
+
+	fmt.Println("Transfer complete, waiting for the switch to write the image and restart...")
+	updated, err := client.WaitForReboot(device.MAC, *wait-time.Since(start))
+	if err != nil {
+		// Some firmware writes the image without restarting
+		fmt.Printf("WARNING: %v\n", err)
+		if updated, err = client.DeviceInfo(device.MAC); err != nil {
+			return fmt.Errorf("upgrade sent, but verification failed: %w", err)
+		}
+	}
+
+	fmt.Println()
+	upgraded := nsdpclient.FirmwareOf(updated)
+	printFirmwareSlots(upgraded)
+	if image.Version == "" {
+		fmt.Println("The image header names no version; check the slots above.")
+		return nil
+	}
+	// A slot that held the version before the upgrade proves nothing
+	slot := upgraded.UpgradedSlot(firmware, image.Version)
+	if slot == 0 {
+		return fmt.Errorf("verification failed: firmware %s is neither in a new slot nor in the next boot slot", image.Version)
+	}
+	fmt.Printf("Firmware %s confirmed in slot %d after %v\n", image.Version, slot, time.Since(start).Round(time.Second))
+	return nil
+}
+
+func formatImageVersion(image *nsdpclient.FirmwareImage) string {
+	if image.Version == "" {
+		return "unknown version"
+	}
+	return image.Version
+}
+
+// transferProgress returns a progress callback printing every 10 percent of
+// the transfer.
+func transferProgress() func(sent, total int) {
+	reported := -1
+	return func(sent, total int) {
+		if total == 0 {
+			return
+		}
+		percent := sent * 100 / total / 10 * 10
+		if percent != reported {
+			reported = percent
+			fmt.Printf("Transferred %3d%% (%d/%d bytes)\n", percent, sent, total)
+		}
+	}
+}
+
+// tftpAddress returns the local address the switch reaches the TFTP server
+// on: that of the interface the switch answered on, or the one routing to
+// its IP address.
+func tftpAddress(device *nsdpclient.DeviceInfo) (net.IP, error) {
+	if device.Interface != "" {
+		iface, err := net.InterfaceByName(device.Interface)
+		if err != nil {
+			return nil, err
+		}
+		return nsdpclient.InterfaceAddress(iface)
+	}
+	if device.IP != nil && !device.IP.IsUnspecified() {
+		return nsdpclient.LocalAddressFor(device.IP)
+	}
+	return nil, fmt.Errorf("cannot determine the address for the TFTP server (select the interface with -i)")
+}
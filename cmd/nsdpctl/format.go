@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+// Helper functions for formatting
+func formatDHCPMode(mode uint8) string {
+	switch mode {
+	case 0:
+		return "Disabled"
+	case 1:
+		return "Enabled"
+	default:
+		return fmt.Sprintf("Unknown (%d)", mode)
+	}
+}
+
+func formatPortStatusByte(status byte) string {
+	switch status {
+	case 0x00:
+		return "Down"
+	case 0x01:
+		return "Up (10 Mbps Half-Duplex)"
+	case 0x02:
+		return "Up (10 Mbps Full-Duplex)"
+	case 0x03:
+		return "Up (100 Mbps Half-Duplex)"
+	case 0x04:
+		return "Up (100 Mbps Full-Duplex)"
+	case 0x05:
+		return "Up (1000 Mbps)"
+	default:
+		return fmt.Sprintf("Unknown Status (0x%02x)", status)
+	}
+}
+
+func formatVLANEngineMode(mode byte) string {
+	switch mode {
+	case 0x00:
+		return "Disabled"
+	case 0x01:
+		return "Basic Port Based"
+	case 0x02:
+		return "Advanced Port Based"
+	case 0x03:
+		return "Basic 802.1Q"
+	case 0x04:
+		return "Advanced 802.1Q"
+	default:
+		return fmt.Sprintf("Unknown Mode (0x%02x)", mode)
+	}
+}
+
+func formatQoSEngineMode(mode byte) string {
+	switch mode {
+	case 0x01:
+		return "Port Based"
+	case 0x02:
+		return "802.1p"
+	default:
+		return fmt.Sprintf("Unknown Mode (0x%02x)", mode)
+	}
+}
+
+func formatEnabledDisabled(value byte) string {
+	switch value {
+	case 0x00:
+		return "Disabled"
+	case 0x01:
+		return "Enabled"
+	case 0x03:
+		return "Enabled"
+	default:
+		return fmt.Sprintf("Unknown (0x%02x)", value)
+	}
+}
+
+// formatEnabled formats a decoded flag like formatEnabledDisabled.
+func formatEnabled(enabled bool) string {
+	if enabled {
+		return formatEnabledDisabled(0x01)
+	}
+	return formatEnabledDisabled(0x00)
+}
+
+func formatRateLimit(limit uint16) string {
+	switch limit {
+	case 0:
+		return "No Limit"
+	case 1:
+		return "512 Kbps"
+	case 2:
+		return "1 Mbps"
+	case 3:
+		return "2 Mbps"
+	case 4:
+		return "4 Mbps"
+	case 5:
+		return "8 Mbps"
+	case 6:
+		return "16 Mbps"
+	case 7:
+		return "32 Mbps"
+	case 8:
+		return "64 Mbps"
+	case 9:
+		return "128 Mbps"
+	case 10:
+		return "256 Mbps"
+	case 11:
+		return "512 Mbps"
+	default:
+		return fmt.Sprintf("Unknown (%d)", limit)
+	}
+}
+
+// rateLimitKbps returns the rate of a rate table index as listed by
+// formatRateLimit in Kbps, 0 meaning no limit, or nil for unknown indexes.
+func rateLimitKbps(limit uint16) *int {
+	if limit > nsdpclient.MaxRateLimit {
+		return nil
+	}
+	kbps := 0
+	if limit > 0 {
+		kbps = 512 << (limit - 1)
+	}
+	return &kbps
+}
+
+// formatByteRate formats a rate in bytes per second with decimal units.
+func formatByteRate(bytesPerSecond float64) string {
+	units := []string{"B/s", "KB/s", "MB/s", "GB/s"}
+	unit := 0
+	for bytesPerSecond >= 1000 && unit < len(units)-1 {
+		bytesPerSecond /= 1000
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", bytesPerSecond, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", bytesPerSecond, units[unit])
+}
+
+func formatQoSPriority(priority byte) string {
+	switch priority {
+	case 0x01:
+		return "High"
+	case 0x02:
+		return "Medium"
+	case 0x03:
+		return "Normal"
+	case 0x04:
+		return "Low"
+	default:
+		return fmt.Sprintf("Unknown (0x%02x)", priority)
+	}
+}
+
+// formatPortList formats ports as a comma separated list, e.g. "[1,2,5]"
+func formatPortList(ports []uint8) string {
+	parts := make([]string, len(ports))
+	for i, port := range ports {
+		parts[i] = fmt.Sprint(port)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+// parsePortList parses a comma separated list of ports and port ranges,
+// e.g. "1,2,5-8". An empty string yields no ports.
+func parsePortList(list string) ([]uint8, error) {
+	var ports []uint8
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		if !isRange {
+			last = first
+		}
+		from, err := strconv.ParseUint(first, 10, 8)
+		if err != nil || from == 0 {
+			return nil, fmt.Errorf("invalid port %q", first)
+		}
+		to, err := strconv.ParseUint(last, 10, 8)
+		if err != nil || to < from {
+			return nil, fmt.Errorf("invalid port range %q", part)
+		}
+		for port := from; port <= to; port++ {
+			ports = append(ports, uint8(port))
+		}
+	}
+	return ports, nil
+}
+
+// parseVLANEngineMode parses a VLAN engine mode given by its name as printed
+// by formatVLANEngineMode (case and spaces ignored) or by number. Only the
+// modes formatVLANEngineMode knows are accepted.
+func parseVLANEngineMode(name string) (byte, error) {
+	if mode, err := strconv.ParseUint(name, 0, 8); err == nil {
+		if strings.HasPrefix(formatVLANEngineMode(byte(mode)), "Unknown") {
+			return 0, fmt.Errorf("unknown VLAN engine mode %q", name)
+		}
+		return byte(mode), nil
+	}
+	mode, err := parseFormatted(name, 0, 0xff, func(v int) string { return formatVLANEngineMode(byte(v)) })
+	if err != nil {
+		return 0, fmt.Errorf("unknown VLAN engine mode %q", name)
+	}
+	return byte(mode), nil
+}
+
+// parseQoSEngineMode parses a QoS engine mode as printed by
+// formatQoSEngineMode, e.g. "port-based" or "802.1p".
+func parseQoSEngineMode(name string) (byte, error) {
+	mode, err := parseFormatted(name, 1, 0xff, func(v int) string { return formatQoSEngineMode(byte(v)) })
+	if err != nil {
+		return 0, fmt.Errorf("unknown QoS engine mode %q (port-based or 802.1p)", name)
+	}
+	return byte(mode), nil
+}
+
+// parseQoSPriority parses a port priority as printed by formatQoSPriority,
+// e.g. "high".
+func parseQoSPriority(name string) (byte, error) {
+	priority, err := parseFormatted(name, 1, 0xff, func(v int) string { return formatQoSPriority(byte(v)) })
+	if err != nil {
+		return 0, fmt.Errorf("unknown priority %q (high, medium, normal or low)", name)
+	}
+	return byte(priority), nil
+}
+
+// parseRateLimit parses a rate limit as printed by formatRateLimit, e.g.
+// "8Mbps" or "no-limit".
+func parseRateLimit(name string) (uint16, error) {
+	limit, err := parseFormatted(name, 0, int(nsdpclient.MaxRateLimit), func(v int) string { return formatRateLimit(uint16(v)) })
+	if err != nil {
+		return 0, fmt.Errorf("unknown rate %q (no-limit, 512Kbps, 1Mbps, 2Mbps, ... 512Mbps)", name)
+	}
+	return uint16(limit), nil
+}
+
+// parseEnabledDisabled parses "enabled" or "disabled" as printed by
+// formatEnabledDisabled.
+func parseEnabledDisabled(name string) (bool, error) {
+	value, err := parseFormatted(name, 0, 1, func(v int) string { return formatEnabledDisabled(byte(v)) })
+	if err != nil {
+		return false, fmt.Errorf("expected enabled or disabled, got %q", name)
+	}
+	return value == 1, nil
+}
+
+// parseFormatted maps a human value back to the value in first..last that
+// format prints for it. Case, spaces, dashes and underscores are ignored.
+// The search stops at the first value format reports as unknown.
+func parseFormatted(name string, first, last int, format func(int) string) (int, error) {
+	normalized := normalizeName(name)
+	for value := first; value <= last; value++ {
+		candidate := format(value)
+		if strings.HasPrefix(candidate, "Unknown") {
+			break
+		}
+		if normalizeName(candidate) == normalized {
+			return value, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown value %q", name)
+}
+
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
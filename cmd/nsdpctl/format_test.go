@@ -0,0 +1,101 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePortList(t *testing.T) {
+	ports, err := parsePortList("1, 3-5,8")
+	if err != nil {
+		t.Fatalf("parsePortList failed: %v", err)
+	}
+	if !reflect.DeepEqual(ports, []uint8{1, 3, 4, 5, 8}) {
+		t.Errorf("Unexpected ports: %v", ports)
+	}
+	if ports, err := parsePortList(""); err != nil || len(ports) != 0 {
+		t.Errorf("Expected no ports, got %v (%v)", ports, err)
+	}
+	for _, list := range []string{"0", "5-3", "a", "1-300"} {
+		if _, err := parsePortList(list); err == nil {
+			t.Errorf("Expected error for %q", list)
+		}
+	}
+}
+
+func TestParseVLANEngineMode(t *testing.T) {
+	for name, expected := range map[string]byte{
+		"Advanced 802.1Q":  0x04,
+		"advanced802.1q":   0x04,
+		"basic port based": 0x01,
+		"0":                0x00,
+	} {
+		mode, err := parseVLANEngineMode(name)
+		if err != nil || mode != expected {
+			t.Errorf("%q: expected %d, got %d (%v)", name, expected, mode, err)
+		}
+	}
+	for _, name := range []string{"802.1X", "5", "255"} {
+		if _, err := parseVLANEngineMode(name); err == nil {
+			t.Errorf("Expected error for unknown mode %q", name)
+		}
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	for name, expected := range map[string]uint16{
+		"8Mbps":    5,
+		"8 mbps":   5,
+		"512Kbps":  1,
+		"512 Mbps": 11,
+		"no-limit": 0,
+	} {
+		limit, err := parseRateLimit(name)
+		if err != nil || limit != expected {
+			t.Errorf("%q: expected %d, got %d (%v)", name, expected, limit, err)
+		}
+	}
+	for _, name := range []string{"5", "3Mbps", "1Gbps", ""} {
+		if _, err := parseRateLimit(name); err == nil {
+			t.Errorf("Expected error for %q", name)
+		}
+	}
+}
+
+func TestParseQoSValues(t *testing.T) {
+	if priority, err := parseQoSPriority("high"); err != nil || priority != 0x01 {
+		t.Errorf("Expected high priority, got %d (%v)", priority, err)
+	}
+	if priority, err := parseQoSPriority("Low"); err != nil || priority != 0x04 {
+		t.Errorf("Expected low priority, got %d (%v)", priority, err)
+	}
+	if _, err := parseQoSPriority("urgent"); err == nil {
+		t.Error("Expected error for unknown priority")
+	}
+	if mode, err := parseQoSEngineMode("port-based"); err != nil || mode != 0x01 {
+		t.Errorf("Expected port based mode, got %d (%v)", mode, err)
+	}
+	if enabled, err := parseEnabledDisabled("Enabled"); err != nil || !enabled {
+		t.Errorf("Expected enabled, got %v (%v)", enabled, err)
+	}
+	if enabled, err := parseEnabledDisabled("disabled"); err != nil || enabled {
+		t.Errorf("Expected disabled, got %v (%v)", enabled, err)
+	}
+	if mode, err := parseQoSEngineMode("802.1p"); err != nil || mode != 0x02 {
+		t.Errorf("Expected 802.1p mode, got %d (%v)", mode, err)
+	}
+}
+
+func TestFormatByteRate(t *testing.T) {
+	for rate, expected := range map[float64]string{
+		0:          "0 B/s",
+		999:        "999 B/s",
+		1500:       "1.5 KB/s",
+		125000000:  "125.0 MB/s",
+		2500000000: "2.5 GB/s",
+	} {
+		if formatted := formatByteRate(rate); formatted != expected {
+			t.Errorf("%v: expected %q, got %q", rate, expected, formatted)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+func runIGMP(g *globalOptions, args []string) error {
+	// Global flags may precede the subcommand
+	fs := newFlagSet("igmp", g)
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		if fs.Arg(0) == "set" {
+			return runIGMPSet(g, fs.Args()[1:])
+		}
+		return fmt.Errorf("unknown subcommand %q (set)", fs.Arg(0))
+	}
+
+	if g.output == "json" {
+		return g.reportDevices(sectionIGMP)
+	}
+
+	return forEachDevice(g, "NSDP IGMP Snooping Configuration", func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) {
+		queryIGMPConfiguration(client, device.MAC, g.verbose)
+	})
+}
+
+func queryIGMPConfiguration(client *nsdpclient.Client, deviceMAC net.HardwareAddr, verbose bool) {
+	fmt.Println("\n--- IGMP Configuration ---")
+
+	// Query IGMP snooping status
+	result := queryCustomParameter(client, deviceMAC, nsdpclient.ParamIGMPSnooping, verbose)
+	if result != nil && len(result) >= 4 {
+		enabled := result[1]
+		vlanID := binary.BigEndian.Uint16(result[2:4])
+		fmt.Printf("IGMP Snooping: %s (VLAN %d)\n", formatEnabledDisabled(enabled), vlanID)
+	}
+
+	// Query block unknown multicast
+	result = queryCustomParameter(client, deviceMAC, nsdpclient.ParamBlockUnknownMcast, verbose)
+	if result != nil && len(result) >= 1 {
+		enabled := result[0]
+		fmt.Printf("Block Unknown Multicast: %s\n", formatEnabledDisabled(enabled))
+	}
+
+	// Query validate IGMPv3
+	result = queryCustomParameter(client, deviceMAC, nsdpclient.ParamValidateIGMPv3, verbose)
+	if result != nil && len(result) >= 1 {
+		enabled := result[0]
+		fmt.Printf("Validate IGMPv3: %s\n", formatEnabledDisabled(enabled))
+	}
+
+	// Query IGMP router ports
+	result = queryCustomParameter(client, deviceMAC, nsdpclient.ParamIGMPRouterPorts, verbose)
+	if result != nil {
+		if verbose {
+			fmt.Printf("  Raw data: %x\n", result)
+		}
+		ports, err := nsdpclient.DecodeIGMPRouterPorts(result)
+		if err != nil {
+			fmt.Printf("IGMP Router Ports Data: %v\n", err)
+		} else if len(ports) == 0 {
+			fmt.Println("IGMP Static Router Ports: None")
+		} else {
+			fmt.Printf("IGMP Static Router Ports: %s\n", formatPortList(ports))
+		}
+	}
+}
+
+func runIGMPSet(g *globalOptions, args []string) error {
+	fs := newFlagSet("igmp set", g)
+	fs.String("snooping", "", "IGMP snooping: enabled or disabled")
+	fs.String("vlan", "", "VLAN IGMP snooping runs on")
+	fs.String("block-unknown", "", "Block unknown multicast: enabled or disabled")
+	fs.String("validate-v3", "", "Validate IGMPv3 IP header: enabled or disabled")
+	fs.String("router-ports", "", "Static router ports, e.g. 8 or none")
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+	fs.Parse(args)
+
+	settings, err := parseIGMPSettings(fs)
+	if err != nil {
+		return err
+	}
+	if settings.IsEmpty() {
+		return fmt.Errorf("nothing to change (use -snooping, -vlan, -block-unknown, -validate-v3 or -router-ports)")
+	}
+
+	return changeDevice(g, *yes, changeSteps[*nsdpclient.IGMPConfig]{
+		title: "NSDP IGMP Snooping Change",
+		what:  "IGMP configuration",
+		read: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) (*nsdpclient.IGMPConfig, error) {
+			return client.IGMPConfig(device.MAC)
+		},
+		validate: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, current *nsdpclient.IGMPConfig) error {
+			return settings.Validate(current)
+		},
+		print: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, current *nsdpclient.IGMPConfig) bool {
+			printIGMPChange(current, settings)
+			return true
+		},
+		apply: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, password string, current *nsdpclient.IGMPConfig) error {
+			return client.ApplyIGMPSettings(device.MAC, password, current, settings)
+		},
+		mismatches: func(updated *nsdpclient.IGMPConfig) []string {
+			var mismatches []string
+			for _, mismatch := range settings.Mismatches(updated) {
+				mismatches = append(mismatches, mismatch+" not changed")
+			}
+			return mismatches
+		},
+	})
+}
+
+// parseIGMPSettings collects the settings given on the command line. Only
+// flags actually set are changed; -router-ports none clears the router ports.
+func parseIGMPSettings(fs *flag.FlagSet) (nsdpclient.IGMPSettings, error) {
+	var settings nsdpclient.IGMPSettings
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		value := f.Value.String()
+		switch f.Name {
+		case "snooping", "block-unknown", "validate-v3":
+			var enabled bool
+			if enabled, err = parseEnabledDisabled(value); err != nil {
+				err = fmt.Errorf("-%s: %v", f.Name, err)
+				return
+			}
+			switch f.Name {
+			case "snooping":
+				settings.Snooping = &enabled
+			case "block-unknown":
+				settings.BlockUnknownMulticast = &enabled
+			case "validate-v3":
+				settings.ValidateIGMPv3 = &enabled
+			}
+		case "vlan":
+			id, parseErr := strconv.ParseUint(value, 10, 16)
+			if parseErr != nil || id < 1 || id > 4093 {
+				err = fmt.Errorf("invalid VLAN ID %q (1-4093)", value)
+				return
+			}
+			vlan := uint16(id)
+			settings.SnoopingVLAN = &vlan
+		case "router-ports":
+			var ports []uint8
+			if normalizeName(value) != "none" {
+				if ports, err = parsePortList(value); err != nil {
+					return
+				}
+			}
+			ports = sortedPorts(ports)
+			settings.RouterPorts = &ports
+		}
+	})
+	return settings, err
+}
+
+// printIGMPChange prints the current and new value of each setting to change.
+func printIGMPChange(current *nsdpclient.IGMPConfig, settings nsdpclient.IGMPSettings) {
+	state := func(value *bool) string {
+		if value == nil {
+			return "-"
+		}
+		return formatEnabled(*value)
+	}
+	fmt.Printf("%-24s %-12s %s\n", "Setting", "Current", "New")
+	if settings.Snooping != nil {
+		fmt.Printf("%-24s %-12s %s\n", "IGMP Snooping", state(current.Snooping), state(settings.Snooping))
+	}
+	if settings.SnoopingVLAN != nil {
+		fmt.Printf("%-24s %-12d %d\n", "IGMP Snooping VLAN", current.SnoopingVLAN, *settings.SnoopingVLAN)
+	}
+	if settings.BlockUnknownMulticast != nil {
+		fmt.Printf("%-24s %-12s %s\n", "Block Unknown Multicast", state(current.BlockUnknownMulticast), state(settings.BlockUnknownMulticast))
+	}
+	if settings.ValidateIGMPv3 != nil {
+		fmt.Printf("%-24s %-12s %s\n", "Validate IGMPv3", state(current.ValidateIGMPv3), state(settings.ValidateIGMPv3))
+	}
+	if settings.RouterPorts != nil {
+		fmt.Printf("%-24s %-12s %s\n", "Static Router Ports", formatPortList(current.RouterPorts), formatPortList(*settings.RouterPorts))
+	}
+}
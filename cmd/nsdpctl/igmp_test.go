@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func newIGMPSetFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("igmp set", flag.ContinueOnError)
+	for _, name := range []string{"snooping", "vlan", "block-unknown", "validate-v3", "router-ports"} {
+		fs.String(name, "", "")
+	}
+	return fs
+}
+
+func TestParseIGMPSettings(t *testing.T) {
+	fs := newIGMPSetFlagSet()
+	if err := fs.Parse([]string{"-snooping", "enabled", "-vlan", "10", "-router-ports", "8,7"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	settings, err := parseIGMPSettings(fs)
+	if err != nil {
+		t.Fatalf("parseIGMPSettings failed: %v", err)
+	}
+	if settings.Snooping == nil || !*settings.Snooping {
+		t.Error("Expected snooping to be enabled")
+	}
+	if settings.SnoopingVLAN == nil || *settings.SnoopingVLAN != 10 {
+		t.Error("Expected snooping VLAN 10")
+	}
+	if settings.BlockUnknownMulticast != nil || settings.ValidateIGMPv3 != nil {
+		t.Error("Expected other flags to stay unchanged")
+	}
+	if settings.RouterPorts == nil || !reflect.DeepEqual(*settings.RouterPorts, []uint8{7, 8}) {
+		t.Errorf("Unexpected router ports: %v", settings.RouterPorts)
+	}
+
+	fs = newIGMPSetFlagSet()
+	fs.Parse([]string{"-router-ports", "none"})
+	settings, err = parseIGMPSettings(fs)
+	if err != nil || settings.RouterPorts == nil || len(*settings.RouterPorts) != 0 {
+		t.Errorf("Expected router ports to be cleared, got %v (%v)", settings.RouterPorts, err)
+	}
+
+	for _, args := range [][]string{{"-snooping", "on?"}, {"-vlan", "4094"}, {"-router-ports", "0"}} {
+		fs = newIGMPSetFlagSet()
+		fs.Parse(args)
+		if _, err := parseIGMPSettings(fs); err == nil {
+			t.Errorf("Expected error for %v", args)
+		}
+	}
+}
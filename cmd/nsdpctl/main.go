@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hdecarne-github/go-nsdp"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+// Options shared by all commands
+type globalOptions struct {
+	interfaceName string
+	target        string
+	inventory     string
+	password      string
+	passwordMode  string
+	timeout       time.Duration
+	verbose       bool
+	output        string
+
+	resolvedTarget *target // Set by validate if -target is given
+	structured     bool    // Set by commands writing structured output
+}
+
+// Supported values of the -o flag
+var outputFormats = []string{"text", "json", "csv", "table"}
+
+type command struct {
+	name        string
+	description string
+	run         func(g *globalOptions, args []string) error
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{"discover", "List NSDP devices with identification, network and port status", runDiscover},
+		{"show", "Show device details (-c for all known parameters)", runShow},
+		{"stats", "Show port statistics", runStats},
+		{"watch", "Show live per-port traffic rates (-interval 5s)", runWatch},
+		{"vlan", "Show VLAN configuration", runVLAN},
+		{"qos", "Show QoS and rate limit configuration", runQoS},
+		{"igmp", "Show IGMP snooping configuration", runIGMP},
+		{"mirror", "Show port mirroring configuration", runMirror},
+		{"cable-test", "Test the cable of a port (-port N)", runCableTest},
+		{"set", "Change device name, location and IP settings", runSet},
+		{"firmware", "Show firmware slots (-fleet to group by version), select the boot slot or upgrade", runFirmware},
+		{"reboot", "Reboot a switch and wait until it is back (-yes)", runReboot},
+		{"factory-reset", "Restore the factory defaults of a switch (-yes)", runFactoryReset},
+		{"reset-stats", "Reset the port statistics (-yes)", runResetStats},
+		{"exporter", "Serve switch metrics for Prometheus (-listen :9753)", runExporter},
+		{"scan-tlv", "Scan a range of TLV codes for supported parameters", runScanTLV},
+	}
+}
+
+func main() {
+	g := &globalOptions{}
+	registerGlobalFlags(flag.CommandLine, g)
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(g, flag.Args()[1:]); err != nil {
+				log.Fatalf("%s: %v", name, err)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [global flags] <command> [command flags]\n\n", os.Args[0])
+	fmt.Fprintln(out, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-13s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintln(out, "\nGlobal flags (also accepted after the command):")
+	flag.PrintDefaults()
+}
+
+// registerGlobalFlags adds the global flags to fs. Each command's flag set
+// registers them too, so they may be given before or after the command name;
+// the current values serve as defaults so settings made earlier are kept.
+func registerGlobalFlags(fs *flag.FlagSet, g *globalOptions) {
+	if g.timeout == 0 {
+		g.timeout = 5 * time.Second
+	}
+	if g.output == "" {
+		g.output = "text"
+	}
+	if g.inventory == "" {
+		g.inventory = defaultInventoryPath()
+	}
+	if g.passwordMode == "" {
+		g.passwordMode = "auto"
+	}
+	fs.StringVar(&g.interfaceName, "i", g.interfaceName, "Network interface name(s), comma separated, or \"all\" (required unless -target is an IP)")
+	fs.StringVar(&g.target, "target", g.target, "Single switch to query: IP address, MAC address or inventory name")
+	fs.StringVar(&g.inventory, "inventory", g.inventory, "Inventory file mapping switch names to addresses")
+	fs.StringVar(&g.password, "p", g.password, "Admin password for configuration changes (default $NSDP_PASSWORD)")
+	fs.StringVar(&g.passwordMode, "password-scheme", g.passwordMode, "Password scheme: auto, plain, xor or hashed")
+	fs.DurationVar(&g.timeout, "t", g.timeout, "Query timeout duration")
+	fs.BoolVar(&g.verbose, "v", g.verbose, "Enable verbose output")
+	fs.StringVar(&g.output, "o", g.output, fmt.Sprintf("Output format %v", outputFormats))
+}
+
+// newFlagSet creates the flag set of a command including the global flags.
+func newFlagSet(name string, g *globalOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	registerGlobalFlags(fs, g)
+	return fs
+}
+
+// validate checks the global options shared by all commands.
+func (g *globalOptions) validate() error {
+	if g.target != "" {
+		resolved, err := resolveTarget(g.target, g.inventory)
+		if err != nil {
+			return err
+		}
+		g.resolvedTarget = resolved
+	}
+	if g.interfaceName == "" && !g.unicast() {
+		return fmt.Errorf("network interface name is required (-i)")
+	}
+
+	supported := false
+	for _, format := range outputFormats {
+		if g.output == format {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("unsupported output format %q (supported: %v)", g.output, outputFormats)
+	}
+	if g.output != "text" && !g.structured {
+		return fmt.Errorf("output format %q is not supported by this command", g.output)
+	}
+
+	if g.unicast() && g.interfaceName == "" {
+		return nil
+	}
+	_, err := g.interfaces()
+	return err
+}
+
+// unicast reports whether requests go to a single switch by IP address
+// instead of being broadcast on the selected interfaces.
+func (g *globalOptions) unicast() bool {
+	return g.resolvedTarget != nil && g.resolvedTarget.ip != nil
+}
+
+// interfaces resolves the interfaces selected with -i. "all" selects every
+// interface NSDP can broadcast on.
+func (g *globalOptions) interfaces() ([]net.Interface, error) {
+	if g.interfaceName == "all" {
+		ifaces, err := nsdpclient.UsableInterfaces()
+		if err != nil {
+			return nil, err
+		}
+		if len(ifaces) == 0 {
+			return nil, fmt.Errorf("no usable network interfaces found")
+		}
+		return ifaces, nil
+	}
+
+	var ifaces []net.Interface
+	for _, name := range strings.Split(g.interfaceName, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		// Get the network interface
+		iface, err := net.InterfaceByName(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get interface %s: %w", name, err)
+		}
+
+		// Get interface addresses
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get interface addresses: %w", err)
+		}
+
+		if len(addrs) == 0 {
+			return nil, fmt.Errorf("interface %s has no addresses", name)
+		}
+		ifaces = append(ifaces, *iface)
+	}
+	if len(ifaces) == 0 {
+		return nil, fmt.Errorf("network interface name is required (-i)")
+	}
+	return ifaces, nil
+}
+
+// newClient validates the global options and connects the NSDP client,
+// either to the switch selected by IP or bound to the selected interfaces.
+func (g *globalOptions) newClient() (*nsdpclient.Client, error) {
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+	scheme, err := nsdpclient.ParsePasswordScheme(g.passwordMode)
+	if err != nil {
+		return nil, err
+	}
+
+	var client *nsdpclient.Client
+	if g.unicast() {
+		client, err = nsdpclient.New(nsdpclient.TargetAddress(g.resolvedTarget.ip), g.timeout, g.verbose)
+	} else {
+		var ifaces []net.Interface
+		if ifaces, err = g.interfaces(); err != nil {
+			return nil, err
+		}
+		client, err = nsdpclient.NewOnInterfaces(ifaces, g.timeout, g.verbose)
+	}
+	if err != nil {
+		return nil, err
+	}
+	client.SetPasswordScheme(scheme)
+	return client, nil
+}
+
+// adminPassword returns the password for write requests, taken from -p or
+// the NSDP_PASSWORD environment variable.
+func (g *globalOptions) adminPassword() (string, error) {
+	if g.password != "" {
+		return g.password, nil
+	}
+	if password := os.Getenv("NSDP_PASSWORD"); password != "" {
+		return password, nil
+	}
+	return "", fmt.Errorf("admin password required (-p or NSDP_PASSWORD)")
+}
+
+// printBanner prints the header shown at the start of each command's output.
+func (g *globalOptions) printBanner(title string) {
+	fmt.Printf("=== %s ===\n", title)
+	if g.interfaceName != "" {
+		fmt.Printf("Interface: %s\n", g.interfaceName)
+	}
+	if g.resolvedTarget != nil {
+		fmt.Printf("Target: %s\n", g.resolvedTarget)
+	}
+	fmt.Printf("Timeout: %v\n", g.timeout)
+	fmt.Println()
+}
+
+// discoverDevices runs a discovery, limited to the switch selected with
+// -target if given, and prints the troubleshooting tips if no device
+// answered.
+func (g *globalOptions) discoverDevices(client *nsdpclient.Client, extra ...nsdp.TLV) ([]*nsdpclient.DeviceInfo, error) {
+	var devices []*nsdpclient.DeviceInfo
+	if g.resolvedTarget != nil && g.resolvedTarget.mac != nil {
+		// Only the addressed device answers
+		device, err := client.DeviceInfo(g.resolvedTarget.mac, extra...)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	} else {
+		var err error
+		if devices, err = client.Discover(extra...); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep structured output on stdout parseable
+	var out io.Writer = os.Stdout
+	if g.structured {
+		out = os.Stderr
+	}
+	if len(devices) == 0 {
+		fmt.Fprintln(out, "No NSDP devices found on the network.")
+		fmt.Fprintln(out, "\nTroubleshooting tips:")
+		fmt.Fprintln(out, "- Ensure switches are on the same network segment")
+		fmt.Fprintln(out, "- Verify switches support NSDP protocol")
+		fmt.Fprintln(out, "- Try increasing timeout with -t flag")
+		fmt.Fprintln(out, "- Use -v flag for verbose output")
+		return nil, nil
+	}
+
+	if !g.structured {
+		fmt.Printf("Found %d NSDP device(s):\n\n", len(devices))
+	}
+	return devices, nil
+}
+
+// forEachDevice discovers all devices and calls query for each of them.
+func forEachDevice(g *globalOptions, title string, query func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo)) error {
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	g.printBanner(title)
+	devices, err := g.discoverDevices(client)
+	if err != nil {
+		return err
+	}
+
+	for i, device := range devices {
+		fmt.Printf("=== Device %d ===\n", i+1)
+		fmt.Printf("Device: %s\n", describeDevice(device))
+		if device.MAC == nil {
+			if g.verbose {
+				fmt.Println("Cannot query device details: no MAC address found")
+			}
+			continue
+		}
+		query(client, device)
+		fmt.Println()
+	}
+	return nil
+}
+
+// singleDevice discovers the one device a configuration change applies to.
+// Several answering devices are an error unless -target selects one.
+func (g *globalOptions) singleDevice(client *nsdpclient.Client) (*nsdpclient.DeviceInfo, error) {
+	devices, err := g.discoverDevices(client)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(devices) == 0:
+		return nil, fmt.Errorf("no device found")
+	case len(devices) > 1:
+		return nil, fmt.Errorf("%d devices found; select one with -target", len(devices))
+	case devices[0].MAC == nil:
+		return nil, fmt.Errorf("device reported no MAC address")
+	}
+	return devices[0], nil
+}
+
+// confirm asks the user a yes/no question on the terminal.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// describeDevice returns a one-line summary identifying a device.
+func describeDevice(device *nsdpclient.DeviceInfo) string {
+	name := device.Name
+	if name == "" {
+		name = "(unnamed)"
+	}
+	description := fmt.Sprintf("%s (%s)", name, device.MAC)
+	if device.Model != "" {
+		description = fmt.Sprintf("%s (%s, %s)", name, device.Model, device.MAC)
+	}
+	if device.Interface != "" {
+		description += " on " + device.Interface
+	}
+	return description
+}
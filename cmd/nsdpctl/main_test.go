@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/hdecarne-github/go-nsdp"
+
+	"nsdp/pkg/nsdpclient"
 )
 
 func TestMain(t *testing.T) {
@@ -19,12 +21,12 @@ func TestQueryNSDPDevices(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create test responder: %v", err)
 	}
-	
+
 	// Add the mock response from the go-nsdp TestStartStop function
 	// This is a real NSDP response that includes device info, port statistics, etc.
 	responder.AddResponses(
 		"0102000000000000bcd07432b8dc6cb0ce1c8394000099d14e534450000000000001000847533130384576330003000773776974636831000400066cb0ce1c839400050000000600040a01000300070004ffff0000000800040a010001000b000100000d0007322e30362e3137000e0000000f0001010c0000030105000c0000030200000c0000030304000c0000030400000c0000030504000c0000030600000c0000030700000c0000030800001000003101000000011b86e2c2000000000d159e3800000000000000000000000000000000000000000000000000000000000000001000003102000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000100000310300000000039bd6ce000000000874984f000000000000000000000000000000000000000000000000000000000000000010000031040000000000133f340000000000cf6d03000000000000000000000000000000000000000000000000000000000000000010000031050000000009668768000000010afa8d1d0000000000000000000000000000000000000000000000000000000000000000100000310600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010000031070000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000001000003108000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000ffff0000")
-	
+
 	// Start the responder
 	err = responder.Start()
 	if err != nil {
@@ -60,7 +62,7 @@ func TestQueryNSDPDevices(t *testing.T) {
 	// Test that we can process each response without panicking
 	for addr, responseMsg := range responseMsgs {
 		t.Logf("Processing response from %s", addr)
-		
+
 		// Verify the response contains expected data
 		tlvs := responseMsg.Body
 		if len(tlvs) == 0 {
@@ -68,9 +70,9 @@ func TestQueryNSDPDevices(t *testing.T) {
 		} else {
 			t.Logf("Response from %s contains %d TLVs", addr, len(tlvs))
 		}
-		
-		// Test that processDeviceResponse works without panicking
-		processDeviceResponse(responseMsg, true) // Use verbose mode for testing
+
+		// Test that printDeviceInfo works without panicking
+		printDeviceInfo(nsdpclient.ParseDeviceInfo(responseMsg), true) // Use verbose mode for testing
 	}
 }
 
@@ -105,7 +107,7 @@ func TestTLVWithValues(t *testing.T) {
 	// Test creating TLVs with actual values
 	mac, _ := net.ParseMAC("00:11:22:33:44:55")
 	ip := net.ParseIP("192.168.1.100")
-	
+
 	tests := []struct {
 		name string
 		tlv  nsdp.TLV
@@ -150,7 +152,7 @@ func TestMessageCreation(t *testing.T) {
 func TestProcessDeviceResponse(t *testing.T) {
 	// Create a mock response message
 	msg := nsdp.NewMessage(nsdp.ReadResponse)
-	
+
 	// Add some test TLVs
 	mac, _ := net.ParseMAC("00:11:22:33:44:55")
 	msg.AppendTLV(nsdp.NewDeviceMAC(mac))
@@ -159,8 +161,9 @@ func TestProcessDeviceResponse(t *testing.T) {
 	msg.AppendTLV(nsdp.NewDeviceIP(net.ParseIP("192.168.1.100")))
 
 	// This should not panic
-	processDeviceResponse(msg, false)
-	processDeviceResponse(msg, true) // Test verbose mode
+	info := nsdpclient.ParseDeviceInfo(msg)
+	printDeviceInfo(info, false)
+	printDeviceInfo(info, true) // Test verbose mode
 }
 
 // Benchmark test for performance
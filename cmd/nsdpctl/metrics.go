@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// metricFamily is a metric with its samples in the Prometheus text
+// exposition format.
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string // gauge or counter
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels []string // Alternating label names and values
+	value  float64
+}
+
+func (f *metricFamily) add(value float64, labels ...string) {
+	f.samples = append(f.samples, metricSample{labels: labels, value: value})
+}
+
+// deviceMetrics converts device reports into metric families. Devices are
+// labelled with their MAC and name, ports additionally with the port number.
+// Values a device did not report are left out.
+func deviceMetrics(reports []*deviceReport) []*metricFamily {
+	info := &metricFamily{name: "nsdp_device_info", help: "Device identification, value is always 1.", kind: "gauge"}
+	firmware := &metricFamily{name: "nsdp_firmware_info", help: "Firmware version per slot, value is always 1.", kind: "gauge"}
+	portCount := &metricFamily{name: "nsdp_ports", help: "Number of ports.", kind: "gauge"}
+	vlans := &metricFamily{name: "nsdp_vlans", help: "Number of configured VLANs.", kind: "gauge"}
+	loop := &metricFamily{name: "nsdp_loop_detection_enabled", help: "Whether loop detection is enabled.", kind: "gauge"}
+	collectErrors := &metricFamily{name: "nsdp_collect_errors", help: "Number of sections that could not be read in the last scrape.", kind: "gauge"}
+	up := &metricFamily{name: "nsdp_port_up", help: "Whether the port has a link.", kind: "gauge"}
+	speed := &metricFamily{name: "nsdp_port_speed_mbps", help: "Link speed of the port in Mbps, 0 if down.", kind: "gauge"}
+	counters := []*metricFamily{
+		{name: "nsdp_port_receive_bytes_total", help: "Bytes received on the port.", kind: "counter"},
+		{name: "nsdp_port_transmit_bytes_total", help: "Bytes sent on the port.", kind: "counter"},
+		{name: "nsdp_port_packets_total", help: "Packets on the port.", kind: "counter"},
+		{name: "nsdp_port_broadcast_packets_total", help: "Broadcast packets on the port.", kind: "counter"},
+		{name: "nsdp_port_multicast_packets_total", help: "Multicast packets on the port.", kind: "counter"},
+		{name: "nsdp_port_errors_total", help: "Errors on the port.", kind: "counter"},
+	}
+
+	for _, r := range reports {
+		device := []string{"mac", r.Identity.MAC, "name", r.Identity.Name}
+		var bootVersion string
+		if r.Firmware != nil {
+			bootVersion = r.Firmware.BootVersion
+			for _, slot := range r.Firmware.Slots {
+				nextBoot := strconv.FormatBool(slot.Slot == r.Firmware.NextSlot)
+				firmware.add(1, append(device, "slot", strconv.Itoa(int(slot.Slot)), "version", slot.Version, "next_boot", nextBoot)...)
+			}
+		}
+		var ip string
+		if r.Network != nil {
+			ip = r.Network.IP
+		}
+		info.add(1, append(device, "model", r.Identity.Model, "ip", ip, "boot_firmware", bootVersion)...)
+		if r.PortCount > 0 {
+			portCount.add(float64(r.PortCount), device...)
+		}
+		if r.VLANs != nil {
+			vlans.add(float64(len(r.VLANs.Memberships)), device...)
+		}
+		if r.LoopDetection != nil {
+			loop.add(boolValue(*r.LoopDetection), device...)
+		}
+		collectErrors.add(float64(len(r.Errors)), device...)
+
+		for _, p := range r.Ports {
+			port := append(device, "port", strconv.Itoa(int(p.Port)))
+			if p.Link != nil {
+				up.add(boolValue(p.Link.Up), port...)
+				speed.add(float64(p.Link.SpeedMbps), port...)
+			}
+			if s := p.Statistics; s != nil {
+				for i, value := range []uint64{s.RXBytes, s.TXBytes, s.Packets, s.Broadcasts, s.Multicasts, s.Errors} {
+					counters[i].add(float64(value), port...)
+				}
+			}
+		}
+	}
+	return append([]*metricFamily{info, firmware, portCount, vlans, loop, collectErrors, up, speed}, counters...)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// writeMetrics writes the families in the text exposition format, skipping
+// those without samples.
+func writeMetrics(w io.Writer, families []*metricFamily) error {
+	for _, f := range families {
+		if len(f.samples) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind); err != nil {
+			return err
+		}
+		for _, s := range f.samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", f.name, formatLabels(s.labels), strconv.FormatFloat(s.value, 'g', -1, 64)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
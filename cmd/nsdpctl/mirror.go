@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+// Port mirroring subcommands changing the configuration
+var mirrorSubcommands = map[string]func(g *globalOptions, args []string) error{
+	"set":     runMirrorSet,
+	"disable": runMirrorDisable,
+}
+
+func runMirror(g *globalOptions, args []string) error {
+	// Global flags may precede the subcommand
+	fs := newFlagSet("mirror", g)
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		if sub, ok := mirrorSubcommands[fs.Arg(0)]; ok {
+			return sub(g, fs.Args()[1:])
+		}
+		return fmt.Errorf("unknown subcommand %q (set or disable)", fs.Arg(0))
+	}
+
+	if g.output == "json" {
+		return g.reportDevices(sectionMirroring)
+	}
+
+	return forEachDevice(g, "NSDP Port Mirroring Configuration", func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) {
+		queryPortMirroring(client, device.MAC, g.verbose)
+	})
+}
+
+func queryPortMirroring(client *nsdpclient.Client, deviceMAC net.HardwareAddr, verbose bool) {
+	fmt.Println("\n--- Port Mirroring ---")
+
+	result := queryCustomParameter(client, deviceMAC, nsdpclient.ParamPortMirroring, verbose)
+	if result == nil {
+		return
+	}
+	if verbose {
+		fmt.Printf("  Raw configuration: %x\n", result)
+	}
+
+	mirroring, err := nsdpclient.DecodePortMirroring(result)
+	if err != nil {
+		fmt.Printf("Port Mirroring: %v\n", err)
+		return
+	}
+	if !mirroring.Enabled() {
+		fmt.Println("Port Mirroring: Disabled")
+		return
+	}
+
+	fmt.Println("Port Mirroring: Enabled")
+	fmt.Printf("Destination Port: %d\n", mirroring.Destination)
+	fmt.Printf("Source Ports: %s\n", formatPortList(mirroring.Sources))
+
+	// The VLAN layout tells whether the destination port carries regular traffic
+	var memberships []nsdpclient.VLANMembership
+	if records := queryCustomParameterRecords(client, deviceMAC, nsdpclient.ParamVLAN8021Q, verbose); records != nil {
+		memberships, _ = nsdpclient.DecodeVLAN8021Q(records)
+	}
+	for _, warning := range nsdpclient.CheckPortMirroring(mirroring, memberships) {
+		fmt.Printf("WARNING: %s\n", warning)
+	}
+}
+
+func runMirrorSet(g *globalOptions, args []string) error {
+	fs := newFlagSet("mirror set", g)
+	dest := fs.Uint("dest", 0, "Destination port receiving the mirrored traffic (required)")
+	sourceList := fs.String("sources", "", "Source ports to mirror, e.g. 1-3 (required)")
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+	fs.Parse(args)
+
+	if *dest < 1 || *dest > 255 {
+		return fmt.Errorf("a destination port between 1 and 255 is required (-dest)")
+	}
+	sources, err := parsePortList(*sourceList)
+	if err != nil {
+		return err
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("at least one source port is required (-sources)")
+	}
+	mirroring := nsdpclient.PortMirroring{Destination: uint8(*dest), Sources: sortedPorts(sources)}
+	if containsPort(mirroring.Sources, mirroring.Destination) {
+		return fmt.Errorf("destination port %d cannot be a mirror source", mirroring.Destination)
+	}
+	return changePortMirroring(g, mirroring, *yes)
+}
+
+func runMirrorDisable(g *globalOptions, args []string) error {
+	fs := newFlagSet("mirror disable", g)
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+	fs.Parse(args)
+
+	return changePortMirroring(g, nsdpclient.PortMirroring{}, *yes)
+}
+
+// changePortMirroring writes a mirroring configuration to the selected
+// device after checking it against the port count.
+func changePortMirroring(g *globalOptions, mirroring nsdpclient.PortMirroring, yes bool) error {
+	var width int
+	return changeDevice(g, yes, changeSteps[nsdpclient.PortMirroring]{
+		title: "NSDP Port Mirroring Change",
+		what:  "port mirroring",
+		read: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) (current nsdpclient.PortMirroring, err error) {
+			current, width, err = client.PortMirroring(device.MAC)
+			return current, err
+		},
+		validate: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, _ nsdpclient.PortMirroring) error {
+			if !mirroring.Enabled() {
+				return nil
+			}
+			if portCount := queryPortCount(client, device.MAC, g.verbose); portCount > 0 {
+				for _, port := range append([]uint8{mirroring.Destination}, mirroring.Sources...) {
+					if int(port) > portCount {
+						return fmt.Errorf("port %d is beyond the %d ports of this model", port, portCount)
+					}
+				}
+			}
+			return nil
+		},
+		print: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, current nsdpclient.PortMirroring) bool {
+			if describeMirroring(current) == describeMirroring(mirroring) {
+				fmt.Printf("Port mirroring is already %s\n", describeMirroring(mirroring))
+				return false
+			}
+			fmt.Printf("Port Mirroring: %s -> %s\n", describeMirroring(current), describeMirroring(mirroring))
+			var memberships []nsdpclient.VLANMembership
+			if records := queryCustomParameterRecords(client, device.MAC, nsdpclient.ParamVLAN8021Q, g.verbose); records != nil {
+				memberships, _ = nsdpclient.DecodeVLAN8021Q(records)
+			}
+			for _, warning := range nsdpclient.CheckPortMirroring(mirroring, memberships) {
+				fmt.Printf("WARNING: %s\n", warning)
+			}
+			return true
+		},
+		apply: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, password string, _ nsdpclient.PortMirroring) error {
+			return client.SetPortMirroring(device.MAC, password, mirroring, width)
+		},
+		mismatches: func(updated nsdpclient.PortMirroring) []string {
+			if describeMirroring(updated) != describeMirroring(mirroring) {
+				return []string{fmt.Sprintf("port mirroring is %s", describeMirroring(updated))}
+			}
+			return nil
+		},
+	})
+}
+
+func describeMirroring(m nsdpclient.PortMirroring) string {
+	if !m.Enabled() {
+		return "Disabled"
+	}
+	return fmt.Sprintf("Destination Port %d, Source Ports %s", m.Destination, formatPortList(m.Sources))
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+// QoS subcommands changing the configuration
+var qosSubcommands = map[string]func(g *globalOptions, args []string) error{
+	"engine":    runQoSEngine,
+	"port":      runQoSPort,
+	"filtering": runQoSFiltering,
+}
+
+func runQoS(g *globalOptions, args []string) error {
+	// Global flags may precede the subcommand
+	fs := newFlagSet("qos", g)
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		if sub, ok := qosSubcommands[fs.Arg(0)]; ok {
+			return sub(g, fs.Args()[1:])
+		}
+		return fmt.Errorf("unknown subcommand %q (engine, port or filtering)", fs.Arg(0))
+	}
+
+	if g.output == "json" {
+		return g.reportDevices(sectionQoS)
+	}
+
+	return forEachDevice(g, "NSDP QoS Configuration", func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) {
+		queryQoSConfiguration(client, device.MAC, g.verbose)
+	})
+}
+
+func queryQoSConfiguration(client *nsdpclient.Client, deviceMAC net.HardwareAddr, verbose bool) {
+	fmt.Println("\n--- QoS Configuration ---")
+
+	// Query QoS engine mode
+	result := queryCustomParameter(client, deviceMAC, nsdpclient.ParamQoSEngine, verbose)
+	if result != nil && len(result) >= 1 {
+		mode := result[0]
+		fmt.Printf("QoS Engine: %s\n", formatQoSEngineMode(mode))
+	}
+
+	// Query per-port priority and rate limits
+	var priorities []nsdpclient.PortPriority
+	records := queryCustomParameterRecords(client, deviceMAC, nsdpclient.ParamQoSPriority, verbose)
+	if records != nil {
+		printRawRecords(records, verbose)
+		decoded, err := nsdpclient.DecodeQoSPriority(records)
+		if err != nil {
+			fmt.Printf("QoS Priority Data: %v\n", err)
+		}
+		priorities = decoded
+	}
+
+	ingress := queryRateLimits(client, deviceMAC, nsdpclient.ParamIngressLimit, verbose)
+	egress := queryRateLimits(client, deviceMAC, nsdpclient.ParamEgressLimit, verbose)
+	printQoSPortTable(priorities, ingress, egress)
+
+	// Query broadcast filtering and the per-port storm control limits it enforces
+	queryStormControl(client, deviceMAC, verbose)
+}
+
+func queryStormControl(client *nsdpclient.Client, deviceMAC net.HardwareAddr, verbose bool) {
+	filtering := queryCustomParameter(client, deviceMAC, nsdpclient.ParamBcastFiltering, verbose)
+	if len(filtering) >= 1 {
+		fmt.Printf("\nBroadcast Filtering: %s\n", formatEnabledDisabled(filtering[0]))
+	}
+
+	limits := queryRateLimits(client, deviceMAC, nsdpclient.ParamStormControl, verbose)
+	if len(limits) == 0 {
+		return
+	}
+
+	sort.Slice(limits, func(i, j int) bool { return limits[i].Port < limits[j].Port })
+	fmt.Printf("%-5s %s\n", "Port", "Broadcast Bandwidth")
+	for _, limit := range limits {
+		fmt.Printf("%-5d %s\n", limit.Port, formatRateLimit(limit.Limit))
+	}
+	if len(filtering) >= 1 && filtering[0] == 0x00 {
+		fmt.Println("Note: broadcast filtering is disabled, storm control limits are not enforced")
+	}
+}
+
+func queryRateLimits(client *nsdpclient.Client, deviceMAC net.HardwareAddr, param uint16, verbose bool) []nsdpclient.PortRateLimit {
+	records := queryCustomParameterRecords(client, deviceMAC, param, verbose)
+	if records == nil {
+		return nil
+	}
+	printRawRecords(records, verbose)
+	limits, err := nsdpclient.DecodeRateLimit(records)
+	if err != nil {
+		fmt.Printf("%s Data: %v\n", nsdpclient.ParamDescription(param), err)
+	}
+	return limits
+}
+
+// printQoSPortTable prints priority, ingress and egress limit of each port
+// in a single table. Values a device did not report are shown as "-".
+func printQoSPortTable(priorities []nsdpclient.PortPriority, ingress, egress []nsdpclient.PortRateLimit) {
+	type portQoS struct {
+		priority, ingress, egress string
+	}
+	ports := make(map[uint8]*portQoS)
+	entry := func(port uint8) *portQoS {
+		if ports[port] == nil {
+			ports[port] = &portQoS{"-", "-", "-"}
+		}
+		return ports[port]
+	}
+	for _, p := range priorities {
+		entry(p.Port).priority = formatQoSPriority(p.Priority)
+	}
+	for _, l := range ingress {
+		entry(l.Port).ingress = formatRateLimit(l.Limit)
+	}
+	for _, l := range egress {
+		entry(l.Port).egress = formatRateLimit(l.Limit)
+	}
+	if len(ports) == 0 {
+		return
+	}
+
+	portNumbers := make([]int, 0, len(ports))
+	for port := range ports {
+		portNumbers = append(portNumbers, int(port))
+	}
+	sort.Ints(portNumbers)
+
+	fmt.Printf("\n%-5s %-10s %-14s %-14s\n", "Port", "Priority", "Ingress Limit", "Egress Limit")
+	for _, port := range portNumbers {
+		q := ports[uint8(port)]
+		fmt.Printf("%-5d %-10s %-14s %-14s\n", port, q.priority, q.ingress, q.egress)
+	}
+}
+
+// qosChange describes a QoS configuration change prepared against the
+// current configuration.
+type qosChange struct {
+	description []string
+	write       func(password string) error                 // Writes the change to the device
+	mismatches  func(config *nsdpclient.QoSConfig) []string // Checks the re-read configuration
+}
+
+// changeQoSConfig runs a QoS configuration change on the selected device. A
+// nil change means there is nothing to do.
+func changeQoSConfig(g *globalOptions, yes bool, prepare func(client *nsdpclient.Client, mac net.HardwareAddr, config *nsdpclient.QoSConfig) (*qosChange, error)) error {
+	var change *qosChange
+	return changeDevice(g, yes, changeSteps[*nsdpclient.QoSConfig]{
+		title: "NSDP QoS Configuration Change",
+		what:  "QoS configuration",
+		read: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) (*nsdpclient.QoSConfig, error) {
+			return client.QoSConfig(device.MAC)
+		},
+		validate: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, config *nsdpclient.QoSConfig) (err error) {
+			change, err = prepare(client, device.MAC, config)
+			return err
+		},
+		print: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, _ *nsdpclient.QoSConfig) bool {
+			if change != nil {
+				fmt.Println(strings.Join(change.description, "\n"))
+			}
+			return change != nil
+		},
+		apply: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, password string, _ *nsdpclient.QoSConfig) error {
+			return change.write(password)
+		},
+		mismatches: func(config *nsdpclient.QoSConfig) []string {
+			return change.mismatches(config)
+		},
+	})
+}
+
+func runQoSEngine(g *globalOptions, args []string) error {
+	fs := newFlagSet("qos engine", g)
+	modeName := fs.String("mode", "", "QoS engine mode: port-based or 802.1p (required)")
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+	fs.Parse(args)
+
+	mode, err := parseQoSEngineMode(*modeName)
+	if err != nil {
+		return err
+	}
+
+	return changeQoSConfig(g, *yes, func(client *nsdpclient.Client, mac net.HardwareAddr, config *nsdpclient.QoSConfig) (*qosChange, error) {
+		if config.Engine == nil {
+			return nil, fmt.Errorf("QoS engine is not supported by this model")
+		}
+		if *config.Engine == mode {
+			fmt.Printf("QoS engine is already %s\n", formatQoSEngineMode(mode))
+			return nil, nil
+		}
+		return &qosChange{
+			description: []string{fmt.Sprintf("QoS Engine: %s -> %s", formatQoSEngineMode(*config.Engine), formatQoSEngineMode(mode))},
+			write: func(password string) error {
+				return client.SetQoSEngine(mac, password, mode)
+			},
+			mismatches: func(config *nsdpclient.QoSConfig) []string {
+				if config.Engine == nil || *config.Engine != mode {
+					return []string{"QoS engine not changed"}
+				}
+				return nil
+			},
+		}, nil
+	})
+}
+
+func runQoSFiltering(g *globalOptions, args []string) error {
+	fs := newFlagSet("qos filtering", g)
+	state := fs.String("state", "", "Broadcast filtering: enabled or disabled (required)")
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+	fs.Parse(args)
+
+	enabled, err := parseEnabledDisabled(*state)
+	if err != nil {
+		return fmt.Errorf("broadcast filtering state: %v (-state)", err)
+	}
+
+	return changeQoSConfig(g, *yes, func(client *nsdpclient.Client, mac net.HardwareAddr, config *nsdpclient.QoSConfig) (*qosChange, error) {
+		if config.BroadcastFiltering == nil {
+			return nil, fmt.Errorf("broadcast filtering is not supported by this model")
+		}
+		if (*config.BroadcastFiltering != 0x00) == enabled {
+			fmt.Printf("Broadcast filtering is already %s\n", formatEnabledDisabled(*config.BroadcastFiltering))
+			return nil, nil
+		}
+		return &qosChange{
+			description: []string{fmt.Sprintf("Broadcast Filtering: %s -> %s", formatEnabledDisabled(*config.BroadcastFiltering), formatEnabled(enabled))},
+			write: func(password string) error {
+				return client.SetBroadcastFiltering(mac, password, enabled)
+			},
+			mismatches: func(config *nsdpclient.QoSConfig) []string {
+				if config.BroadcastFiltering == nil || (*config.BroadcastFiltering != 0x00) != enabled {
+					return []string{"broadcast filtering not changed"}
+				}
+				return nil
+			},
+		}, nil
+	})
+}
+
+func runQoSPort(g *globalOptions, args []string) error {
+	fs := newFlagSet("qos port", g)
+	portList := fs.String("port", "", "Ports to change, e.g. 1-4,7 (required)")
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+	fs.String("priority", "", "Port priority: high, medium, normal or low")
+	fs.String("ingress", "", "Ingress rate limit, e.g. 8Mbps or no-limit")
+	fs.String("egress", "", "Egress rate limit, e.g. 8Mbps or no-limit")
+	fs.String("storm", "", "Broadcast storm control limit, e.g. 1Mbps or no-limit")
+	fs.Parse(args)
+
+	ports, err := parsePortList(*portList)
+	if err != nil {
+		return err
+	}
+	if len(ports) == 0 {
+		return fmt.Errorf("at least one port is required (-port)")
+	}
+	ports = sortedPorts(ports)
+	settings, err := parsePortQoSSettings(fs)
+	if err != nil {
+		return err
+	}
+	if settings.IsEmpty() {
+		return fmt.Errorf("nothing to change (use -priority, -ingress, -egress or -storm)")
+	}
+
+	return changeQoSConfig(g, *yes, func(client *nsdpclient.Client, mac net.HardwareAddr, config *nsdpclient.QoSConfig) (*qosChange, error) {
+		if err := settings.Validate(config, ports); err != nil {
+			return nil, err
+		}
+		var description []string
+		for _, port := range ports {
+			description = append(description, describePortQoSChange(port, settings, config)...)
+		}
+		if settings.StormControl != nil && config.BroadcastFiltering != nil && *config.BroadcastFiltering == 0x00 {
+			description = append(description, "Note: broadcast filtering is disabled, storm control limits are not enforced (qos filtering -state enabled)")
+		}
+		return &qosChange{
+			description: description,
+			write: func(password string) error {
+				return client.SetPortQoS(mac, password, config, ports, settings)
+			},
+			mismatches: func(config *nsdpclient.QoSConfig) []string {
+				return settings.Mismatches(config, ports)
+			},
+		}, nil
+	})
+}
+
+// parsePortQoSSettings parses the per-port values given on the command line.
+// Flags that were not given are left unchanged.
+func parsePortQoSSettings(fs *flag.FlagSet) (nsdpclient.PortQoSSettings, error) {
+	var settings nsdpclient.PortQoSSettings
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		value := f.Value.String()
+		switch f.Name {
+		case "priority":
+			var priority byte
+			if priority, err = parseQoSPriority(value); err == nil {
+				settings.Priority = &priority
+			}
+		case "ingress", "egress", "storm":
+			var limit uint16
+			if limit, err = parseRateLimit(value); err != nil {
+				return
+			}
+			switch f.Name {
+			case "ingress":
+				settings.Ingress = &limit
+			case "egress":
+				settings.Egress = &limit
+			case "storm":
+				settings.StormControl = &limit
+			}
+		}
+	})
+	return settings, err
+}
+
+// describePortQoSChange describes the changes of a port as "old -> new" lines.
+func describePortQoSChange(port uint8, settings nsdpclient.PortQoSSettings, config *nsdpclient.QoSConfig) []string {
+	var lines []string
+	if settings.Priority != nil {
+		current := "-"
+		for _, p := range config.Priorities {
+			if p.Port == port {
+				current = formatQoSPriority(p.Priority)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("Port %d: Priority: %s -> %s", port, current, formatQoSPriority(*settings.Priority)))
+	}
+	for _, limit := range []struct {
+		name   string
+		value  *uint16
+		limits []nsdpclient.PortRateLimit
+	}{
+		{"Ingress Limit", settings.Ingress, config.Ingress},
+		{"Egress Limit", settings.Egress, config.Egress},
+		{"Storm Control", settings.StormControl, config.StormControl},
+	} {
+		if limit.value == nil {
+			continue
+		}
+		current := "-"
+		for _, l := range limit.limits {
+			if l.Port == port {
+				current = formatRateLimit(l.Limit)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("Port %d: %s: %s -> %s", port, limit.name, current, formatRateLimit(*limit.value)))
+	}
+	return lines
+}
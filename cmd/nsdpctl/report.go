@@ -0,0 +1,454 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+
+	"github.com/hdecarne-github/go-nsdp"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+// reportSchemaVersion is the version of the JSON device document. Adding
+// fields keeps the version; renaming or removing fields or changing their
+// type increments it.
+const reportSchemaVersion = 1
+
+// deviceReport is the structured view of a device written with -o json, one
+// document per device. Sections a command does not query are omitted.
+type deviceReport struct {
+	SchemaVersion int              `json:"schema_version"`
+	Identity      identityReport   `json:"identity"`
+	Network       *networkReport   `json:"network,omitempty"`
+	Firmware      *firmwareReport  `json:"firmware,omitempty"`
+	PortCount     int              `json:"port_count,omitempty"`
+	Ports         []*portReport    `json:"ports,omitempty"`
+	VLANs         *vlanReport      `json:"vlans,omitempty"`
+	QoS           *qosReport       `json:"qos,omitempty"`
+	IGMP          *igmpReport      `json:"igmp,omitempty"`
+	Mirroring     *mirroringReport `json:"mirroring,omitempty"`
+	LoopDetection *bool            `json:"loop_detection,omitempty"`
+	Errors        []string         `json:"errors,omitempty"` // Sections that could not be read
+}
+
+type identityReport struct {
+	MAC       string `json:"mac"`
+	Model     string `json:"model,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Location  string `json:"location,omitempty"`
+	Interface string `json:"interface,omitempty"`
+}
+
+type networkReport struct {
+	IP      string `json:"ip,omitempty"`
+	Netmask string `json:"netmask,omitempty"`
+	Gateway string `json:"gateway,omitempty"`
+	DHCP    *bool  `json:"dhcp,omitempty"`
+}
+
+// firmwareReport holds the slot versions, the next boot slot and the
+// version in it. NSDP does not report the slot the device runs from.
+type firmwareReport struct {
+	Slots       []firmwareSlotReport `json:"slots"`
+	NextSlot    uint8                `json:"next_slot,omitempty"`
+	BootVersion string               `json:"boot_version,omitempty"`
+}
+
+type firmwareSlotReport struct {
+	Slot    uint8  `json:"slot"`
+	Version string `json:"version"`
+}
+
+type portReport struct {
+	Port       uint8             `json:"port"`
+	Link       *linkReport       `json:"link,omitempty"`
+	Statistics *statisticsReport `json:"statistics,omitempty"`
+}
+
+type linkReport struct {
+	Up         bool  `json:"up"`
+	SpeedMbps  int   `json:"speed_mbps,omitempty"`
+	FullDuplex bool  `json:"full_duplex,omitempty"`
+	Status     uint8 `json:"status"` // Status code as reported by the device
+}
+
+type statisticsReport struct {
+	RXBytes    uint64 `json:"rx_bytes"`
+	TXBytes    uint64 `json:"tx_bytes"`
+	Packets    uint64 `json:"packets"`
+	Broadcasts uint64 `json:"broadcasts"`
+	Multicasts uint64 `json:"multicasts"`
+	Errors     uint64 `json:"errors"`
+}
+
+type vlanReport struct {
+	Engine      string                 `json:"engine,omitempty"`
+	Memberships []vlanMembershipReport `json:"memberships"`
+	PVIDs       []pvidReport           `json:"pvids,omitempty"`
+}
+
+type vlanMembershipReport struct {
+	ID       uint16 `json:"id"`
+	Untagged []int  `json:"untagged"`
+	Tagged   []int  `json:"tagged"`
+}
+
+type pvidReport struct {
+	Port uint8  `json:"port"`
+	VLAN uint16 `json:"vlan"`
+}
+
+type qosReport struct {
+	Engine             string           `json:"engine,omitempty"`
+	BroadcastFiltering *bool            `json:"broadcast_filtering,omitempty"`
+	Ports              []*qosPortReport `json:"ports,omitempty"`
+}
+
+// qosPortReport holds the QoS settings of a port. Rates are in Kbps, 0
+// meaning no limit; settings the device does not report are omitted.
+type qosPortReport struct {
+	Port             uint8  `json:"port"`
+	Priority         string `json:"priority,omitempty"`
+	IngressKbps      *int   `json:"ingress_kbps,omitempty"`
+	EgressKbps       *int   `json:"egress_kbps,omitempty"`
+	StormControlKbps *int   `json:"storm_control_kbps,omitempty"`
+}
+
+type igmpReport struct {
+	Snooping              *bool  `json:"snooping,omitempty"`
+	SnoopingVLAN          uint16 `json:"snooping_vlan,omitempty"`
+	BlockUnknownMulticast *bool  `json:"block_unknown_multicast,omitempty"`
+	ValidateIGMPv3        *bool  `json:"validate_igmpv3,omitempty"`
+	RouterPorts           []int  `json:"router_ports,omitempty"`
+}
+
+type mirroringReport struct {
+	Enabled     bool     `json:"enabled"`
+	Destination uint8    `json:"destination,omitempty"`
+	Sources     []int    `json:"sources,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+// reportSection selects the parts of a device report that need extra
+// requests. Identity, network and firmware come with the discovery.
+type reportSection int
+
+const (
+	sectionPorts reportSection = 1 << iota
+	sectionStatistics
+	sectionVLANs
+	sectionQoS
+	sectionIGMP
+	sectionMirroring
+	sectionLoopDetection
+
+	sectionAll = sectionPorts | sectionStatistics | sectionVLANs | sectionQoS | sectionIGMP | sectionMirroring | sectionLoopDetection
+)
+
+// newDeviceReport creates the report of a device from its discovery
+// response.
+func newDeviceReport(device *nsdpclient.DeviceInfo) *deviceReport {
+	report := &deviceReport{
+		SchemaVersion: reportSchemaVersion,
+		Identity: identityReport{
+			Model:     device.Model,
+			Name:      device.Name,
+			Location:  device.Location,
+			Interface: device.Interface,
+		},
+	}
+	if device.MAC != nil {
+		report.Identity.MAC = device.MAC.String()
+	}
+
+	if device.IP != nil || device.Netmask != nil || device.Gateway != nil || device.DHCPMode != nil {
+		report.Network = &networkReport{}
+		if device.IP != nil {
+			report.Network.IP = device.IP.String()
+		}
+		if device.Netmask != nil {
+			report.Network.Netmask = device.Netmask.String()
+		}
+		if device.Gateway != nil {
+			report.Network.Gateway = device.Gateway.String()
+		}
+		if device.DHCPMode != nil {
+			dhcp := *device.DHCPMode == nsdpclient.DHCPEnabled
+			report.Network.DHCP = &dhcp
+		}
+	}
+
+	firmware := nsdpclient.FirmwareOf(device)
+	if firmware.Version(1) != "" || firmware.DualImage() {
+		report.Firmware = &firmwareReport{NextSlot: firmware.NextSlot, BootVersion: firmware.BootVersion()}
+		for slot := uint8(1); slot <= 2; slot++ {
+			if version := firmware.Version(slot); version != "" {
+				report.Firmware.Slots = append(report.Firmware.Slots, firmwareSlotReport{Slot: slot, Version: version})
+			}
+		}
+	}
+
+	for _, ps := range device.Ports {
+		report.port(ps.Port).Link = newLinkReport(ps.Status)
+	}
+	if len(device.VLANs) > 0 {
+		report.VLANs = &vlanReport{}
+		for _, vi := range device.VLANs {
+			report.VLANs.Memberships = append(report.VLANs.Memberships, vlanMembershipReport{
+				ID:       vi.VLANID,
+				Untagged: portNumbers(vi.UntaggedPorts),
+				Tagged:   portNumbers(vi.TaggedPorts),
+			})
+		}
+	}
+	return report
+}
+
+// port returns the entry of a port, adding it if needed.
+func (r *deviceReport) port(port uint8) *portReport {
+	for _, p := range r.Ports {
+		if p.Port == port {
+			return p
+		}
+	}
+	p := &portReport{Port: port}
+	r.Ports = append(r.Ports, p)
+	sort.Slice(r.Ports, func(i, j int) bool { return r.Ports[i].Port < r.Ports[j].Port })
+	return p
+}
+
+func (r *deviceReport) addError(section string, err error) {
+	r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", section, err))
+}
+
+// collect reads the selected sections from the device. Sections that fail
+// are recorded in Errors and left out.
+func (r *deviceReport) collect(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, sections reportSection) {
+	mac := device.MAC
+	if sections&(sectionPorts|sectionStatistics) != 0 {
+		if ports, err := client.Ports(mac); err != nil {
+			r.addError("ports", err)
+		} else {
+			r.addPorts(ports, sections&sectionStatistics != 0)
+		}
+	}
+	if sections&sectionVLANs != 0 {
+		if config, err := client.VLANConfig(mac); err != nil {
+			r.addError("vlans", err)
+		} else {
+			r.VLANs = newVLANReport(config)
+		}
+	}
+	if sections&sectionQoS != 0 {
+		if config, err := client.QoSConfig(mac); err != nil {
+			r.addError("qos", err)
+		} else {
+			r.QoS = newQoSReport(config)
+		}
+	}
+	if sections&sectionIGMP != 0 {
+		if config, err := client.IGMPConfig(mac); err != nil {
+			r.addError("igmp", err)
+		} else {
+			r.IGMP = newIGMPReport(config)
+		}
+	}
+	if sections&sectionMirroring != 0 {
+		r.collectMirroring(client, mac)
+	}
+	if sections&sectionLoopDetection != 0 {
+		if value, err := client.ReadParam(mac, nsdpclient.ParamLoopDetection); err != nil {
+			r.addError("loop_detection", err)
+		} else if len(value) >= 1 {
+			enabled := value[0] != 0x00
+			r.LoopDetection = &enabled
+		}
+	}
+}
+
+func (r *deviceReport) addPorts(ports *nsdpclient.PortReport, statistics bool) {
+	r.PortCount = ports.PortCount
+	for _, ps := range ports.Status {
+		r.port(ps.Port).Link = newLinkReport(ps.Status)
+	}
+	if !statistics {
+		return
+	}
+	for _, stat := range ports.Statistics {
+		r.port(stat.Port).Statistics = &statisticsReport{
+			RXBytes:    stat.Received,
+			TXBytes:    stat.Sent,
+			Packets:    stat.Packets,
+			Broadcasts: stat.Broadcasts,
+			Multicasts: stat.Multicasts,
+			Errors:     stat.Errors,
+		}
+	}
+}
+
+func (r *deviceReport) collectMirroring(client *nsdpclient.Client, mac net.HardwareAddr) {
+	mirroring, _, err := client.PortMirroring(mac)
+	if err != nil {
+		r.addError("mirroring", err)
+		return
+	}
+	r.Mirroring = &mirroringReport{Enabled: mirroring.Enabled()}
+	if !mirroring.Enabled() {
+		return
+	}
+	r.Mirroring.Destination = mirroring.Destination
+	r.Mirroring.Sources = portNumbers(mirroring.Sources)
+	var memberships []nsdpclient.VLANMembership
+	if config, err := client.VLANConfig(mac); err == nil {
+		memberships = config.Memberships
+	}
+	r.Mirroring.Warnings = nsdpclient.CheckPortMirroring(mirroring, memberships)
+}
+
+// newLinkReport decodes a port status code as listed by formatPortStatusByte.
+func newLinkReport(status uint8) *linkReport {
+	link := &linkReport{Up: status != 0x00, Status: status}
+	switch status {
+	case 0x01, 0x02:
+		link.SpeedMbps = 10
+	case 0x03, 0x04:
+		link.SpeedMbps = 100
+	case 0x05:
+		link.SpeedMbps = 1000
+	}
+	link.FullDuplex = status == 0x02 || status == 0x04 || status == 0x05
+	return link
+}
+
+func newVLANReport(config *nsdpclient.VLANConfig) *vlanReport {
+	report := &vlanReport{Memberships: []vlanMembershipReport{}}
+	if config.Engine != nil {
+		report.Engine = formatVLANEngineMode(*config.Engine)
+	}
+	for _, m := range config.Memberships {
+		report.Memberships = append(report.Memberships, vlanMembershipReport{
+			ID:       m.ID,
+			Untagged: portNumbers(m.Untagged()),
+			Tagged:   portNumbers(m.Tagged),
+		})
+	}
+	for _, pvid := range config.PVIDs {
+		report.PVIDs = append(report.PVIDs, pvidReport{Port: pvid.Port, VLAN: pvid.VLAN})
+	}
+	return report
+}
+
+func newQoSReport(config *nsdpclient.QoSConfig) *qosReport {
+	report := &qosReport{}
+	if config.Engine != nil {
+		report.Engine = formatQoSEngineMode(*config.Engine)
+	}
+	if config.BroadcastFiltering != nil {
+		enabled := *config.BroadcastFiltering != 0x00
+		report.BroadcastFiltering = &enabled
+	}
+	port := func(number uint8) *qosPortReport {
+		for _, p := range report.Ports {
+			if p.Port == number {
+				return p
+			}
+		}
+		p := &qosPortReport{Port: number}
+		report.Ports = append(report.Ports, p)
+		return p
+	}
+	for _, p := range config.Priorities {
+		port(p.Port).Priority = formatQoSPriority(p.Priority)
+	}
+	for _, l := range config.Ingress {
+		port(l.Port).IngressKbps = rateLimitKbps(l.Limit)
+	}
+	for _, l := range config.Egress {
+		port(l.Port).EgressKbps = rateLimitKbps(l.Limit)
+	}
+	for _, l := range config.StormControl {
+		port(l.Port).StormControlKbps = rateLimitKbps(l.Limit)
+	}
+	sort.Slice(report.Ports, func(i, j int) bool { return report.Ports[i].Port < report.Ports[j].Port })
+	return report
+}
+
+func newIGMPReport(config *nsdpclient.IGMPConfig) *igmpReport {
+	report := &igmpReport{
+		Snooping:              config.Snooping,
+		BlockUnknownMulticast: config.BlockUnknownMulticast,
+		ValidateIGMPv3:        config.ValidateIGMPv3,
+	}
+	if config.Snooping != nil {
+		report.SnoopingVLAN = config.SnoopingVLAN
+	}
+	if config.BitmapWidth > 0 {
+		report.RouterPorts = portNumbers(config.RouterPorts)
+	}
+	return report
+}
+
+// portNumbers converts ports for JSON, which would encode []uint8 as base64.
+func portNumbers(ports []uint8) []int {
+	numbers := make([]int, len(ports))
+	for i, port := range ports {
+		numbers[i] = int(port)
+	}
+	return numbers
+}
+
+// writeReport writes a device report as an indented JSON document.
+func writeReport(w io.Writer, report *deviceReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// reportDevices discovers the devices and writes a JSON document for each
+// of them with the selected sections.
+func (g *globalOptions) reportDevices(sections reportSection, extra ...nsdp.TLV) error {
+	reports, err := g.collectReports(sections, extra...)
+	if err != nil {
+		return err
+	}
+	for _, report := range reports {
+		if err := writeReport(os.Stdout, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectReports discovers the devices and reads the selected sections of
+// each of them.
+func (g *globalOptions) collectReports(sections reportSection, extra ...nsdp.TLV) ([]*deviceReport, error) {
+	g.structured = true
+	client, err := g.newClient()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return g.readReports(client, sections, extra...)
+}
+
+// readReports discovers the devices with an open client and reads the
+// selected sections of each of them.
+func (g *globalOptions) readReports(client *nsdpclient.Client, sections reportSection, extra ...nsdp.TLV) ([]*deviceReport, error) {
+	devices, err := g.discoverDevices(client, extra...)
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]*deviceReport, 0, len(devices))
+	for _, device := range devices {
+		report := newDeviceReport(device)
+		if device.MAC != nil {
+			report.collect(client, device, sections)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hdecarne-github/go-nsdp"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+func TestNewDeviceReport(t *testing.T) {
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+	dhcp := nsdpclient.DHCPEnabled
+	report := newDeviceReport(&nsdpclient.DeviceInfo{
+		MAC:            mac,
+		Model:          "GS108Ev3",
+		Name:           "switch1",
+		IP:             net.IPv4(10, 1, 0, 3),
+		DHCPMode:       &dhcp,
+		FWVersionSlot1: "2.06.17",
+		Ports:          []*nsdp.PortStatus{{Port: 2, Status: 0x05}, {Port: 1, Status: 0x00}},
+		VLANs:          []*nsdp.VLANInfo{{VLANID: 1, UntaggedPorts: []uint8{1, 2}}},
+	})
+
+	if report.SchemaVersion != reportSchemaVersion || report.Identity.MAC != "6c:b0:ce:1c:83:94" {
+		t.Errorf("Unexpected identity: %+v", report.Identity)
+	}
+	if report.Network == nil || report.Network.IP != "10.1.0.3" || report.Network.DHCP == nil || !*report.Network.DHCP {
+		t.Errorf("Unexpected network: %+v", report.Network)
+	}
+	if report.Firmware == nil || report.Firmware.NextSlot != 0 || len(report.Firmware.Slots) != 1 {
+		t.Errorf("Unexpected firmware: %+v", report.Firmware)
+	}
+	if len(report.Ports) != 2 || report.Ports[0].Port != 1 || report.Ports[0].Link.Up {
+		t.Fatalf("Unexpected ports: %+v", report.Ports)
+	}
+	if link := report.Ports[1].Link; !link.Up || link.SpeedMbps != 1000 || !link.FullDuplex {
+		t.Errorf("Unexpected link of port 2: %+v", link)
+	}
+	if report.VLANs == nil || !reflect.DeepEqual(report.VLANs.Memberships[0].Untagged, []int{1, 2}) {
+		t.Errorf("Unexpected VLANs: %+v", report.VLANs)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	report := newDeviceReport(&nsdpclient.DeviceInfo{Model: "GS108Ev3"})
+	report.Mirroring = &mirroringReport{Enabled: true, Destination: 8, Sources: portNumbers([]uint8{1, 2})}
+	report.addError("qos", net.ErrClosed)
+
+	var buf bytes.Buffer
+	if err := writeReport(&buf, report); err != nil {
+		t.Fatalf("writeReport failed: %v", err)
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &document); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if document["schema_version"] != float64(reportSchemaVersion) {
+		t.Errorf("Unexpected schema version: %v", document["schema_version"])
+	}
+	for _, section := range []string{"network", "firmware", "ports", "qos"} {
+		if _, ok := document[section]; ok {
+			t.Errorf("Expected %s to be omitted", section)
+		}
+	}
+	mirroring := document["mirroring"].(map[string]interface{})
+	if !reflect.DeepEqual(mirroring["sources"], []interface{}{float64(1), float64(2)}) {
+		t.Errorf("Unexpected sources: %v", mirroring["sources"])
+	}
+	if errors := document["errors"].([]interface{}); len(errors) != 1 {
+		t.Errorf("Unexpected errors: %v", errors)
+	}
+}
+
+func TestNewQoSReport(t *testing.T) {
+	engine := nsdpclient.QoSEnginePortBased
+	report := newQoSReport(&nsdpclient.QoSConfig{
+		Engine:     &engine,
+		Priorities: []nsdpclient.PortPriority{{Port: 2, Priority: 0x01}, {Port: 1, Priority: 0x03}},
+		Ingress:    []nsdpclient.PortRateLimit{{Port: 1, Limit: 0}, {Port: 2, Limit: 5}},
+	})
+
+	if len(report.Ports) != 2 || report.Ports[0].Port != 1 {
+		t.Fatalf("Unexpected ports: %+v", report.Ports)
+	}
+	if p := report.Ports[0]; p.IngressKbps == nil || *p.IngressKbps != 0 || p.EgressKbps != nil {
+		t.Errorf("Unexpected limits of port 1: %+v", p)
+	}
+	if p := report.Ports[1]; p.IngressKbps == nil || *p.IngressKbps != 8192 {
+		t.Errorf("Unexpected limits of port 2: %+v", p)
+	}
+}
+
+func TestRateLimitKbps(t *testing.T) {
+	for limit, expected := range map[uint16]int{0: 0, 1: 512, 2: 1024, 11: 524288} {
+		if kbps := rateLimitKbps(limit); kbps == nil || *kbps != expected {
+			t.Errorf("%d: expected %d, got %v", limit, expected, kbps)
+		}
+	}
+	if kbps := rateLimitKbps(nsdpclient.MaxRateLimit + 1); kbps != nil {
+		t.Errorf("Expected nil for unknown limit, got %d", *kbps)
+	}
+}
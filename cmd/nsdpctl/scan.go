@@ -2,9 +2,7 @@ package main
 
 import (
 	"encoding/hex"
-	"flag"
 	"fmt"
-	"log"
 	"net"
 	"os"
 	"sort"
@@ -12,7 +10,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/hdecarne-github/go-nsdp"
+	"nsdp/pkg/nsdpclient"
 )
 
 type TLVResponse struct {
@@ -23,85 +21,70 @@ type TLVResponse struct {
 }
 
 type DiscoveryResults struct {
-	DeviceMAC     string
-	DeviceName    string
-	DeviceModel   string
-	ValidTLVs     []TLVResponse
-	TotalTested   int
-	TotalValid    int
-	ScanDuration  time.Duration
+	DeviceMAC    string
+	DeviceName   string
+	DeviceModel  string
+	ValidTLVs    []TLVResponse
+	TotalTested  int
+	TotalValid   int
+	ScanDuration time.Duration
 }
 
-func main() {
-	var (
-		interfaceName = flag.String("i", "", "Network interface name (required)")
-		timeout       = flag.Duration("t", 10*time.Second, "Query timeout duration")
-		verbose       = flag.Bool("v", false, "Enable verbose output")
-		startHex      = flag.String("start", "0000", "Starting TLV hex value (default: 0000)")
-		endHex        = flag.String("end", "FFFF", "Ending TLV hex value (default: FFFF)")
-		outputFile    = flag.String("o", "", "Output file for results (optional)")
-		batchSize     = flag.Int("batch", 100, "Number of TLVs to test per batch")
-		delay         = flag.Duration("delay", 100*time.Millisecond, "Delay between batches")
-	)
-	flag.Parse()
-
-	if *interfaceName == "" {
-		fmt.Println("Error: Network interface name is required")
-		flag.Usage()
-		os.Exit(1)
-	}
+func runScanTLV(g *globalOptions, args []string) error {
+	fs := newFlagSet("scan-tlv", g)
+	startHex := fs.String("start", "0000", "Starting TLV hex value")
+	endHex := fs.String("end", "FFFF", "Ending TLV hex value")
+	outputFile := fs.String("file", "", "Output file for results (optional)")
+	batchSize := fs.Int("batch", 100, "Number of TLVs to test per batch")
+	delay := fs.Duration("delay", 100*time.Millisecond, "Delay between batches")
+	fs.Parse(args)
 
 	// Parse start and end values
 	startVal, err := strconv.ParseUint(*startHex, 16, 16)
 	if err != nil {
-		log.Fatalf("Invalid start hex value: %v", err)
+		return fmt.Errorf("invalid start hex value: %w", err)
 	}
 
 	endVal, err := strconv.ParseUint(*endHex, 16, 16)
 	if err != nil {
-		log.Fatalf("Invalid end hex value: %v", err)
+		return fmt.Errorf("invalid end hex value: %w", err)
 	}
 
 	if startVal > endVal {
-		log.Fatalf("Start value (0x%04X) must be <= end value (0x%04X)", startVal, endVal)
+		return fmt.Errorf("start value (0x%04X) must be <= end value (0x%04X)", startVal, endVal)
+	}
+
+	if *batchSize < 1 {
+		return fmt.Errorf("batch size must be at least 1")
+	}
+
+	client, err := g.newClient()
+	if err != nil {
+		return err
 	}
+	defer client.Close()
 
-	fmt.Printf("=== NSDP TLV Discovery Tool ===\n")
-	fmt.Printf("Interface: %s\n", *interfaceName)
-	fmt.Printf("Timeout: %v\n", *timeout)
+	g.printBanner("NSDP TLV Discovery Tool")
 	fmt.Printf("Scanning range: 0x%04X to 0x%04X (%d TLVs)\n", startVal, endVal, endVal-startVal+1)
 	fmt.Printf("Batch size: %d\n", *batchSize)
 	fmt.Printf("Delay between batches: %v\n", *delay)
 	fmt.Println()
 
-	// Get network interface
-	iface, err := net.InterfaceByName(*interfaceName)
-	if err != nil {
-		log.Fatalf("Failed to get interface %s: %v", *interfaceName, err)
-	}
-
 	// Discover devices first
 	fmt.Println("Discovering NSDP devices...")
-	devices, err := nsdp.Discover(iface, *timeout)
+	devices, err := g.discoverDevices(client)
 	if err != nil {
-		log.Fatalf("Failed to discover devices: %v", err)
-	}
-
-	if len(devices) == 0 {
-		fmt.Println("No NSDP devices found")
-		os.Exit(1)
+		return err
 	}
 
-	fmt.Printf("Found %d device(s)\n\n", len(devices))
-
 	// Process each device
 	for i, device := range devices {
 		fmt.Printf("=== Device %d ===\n", i+1)
-		results := scanDevice(device, iface, uint16(startVal), uint16(endVal), *batchSize, *delay, *timeout, *verbose)
-		
+		results := scanDevice(client, device, uint16(startVal), uint16(endVal), *batchSize, *delay, g.verbose)
+
 		// Display results
 		displayResults(results)
-		
+
 		// Save to file if requested
 		if *outputFile != "" {
 			filename := *outputFile
@@ -116,28 +99,23 @@ func main() {
 			}
 			saveResults(results, filename)
 		}
-		
+
 		fmt.Println()
 	}
+	return nil
 }
 
-func scanDevice(device *nsdp.Device, iface *net.Interface, start, end uint16, batchSize int, delay time.Duration, timeout time.Duration, verbose bool) DiscoveryResults {
+func scanDevice(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, start, end uint16, batchSize int, delay time.Duration, verbose bool) DiscoveryResults {
 	results := DiscoveryResults{
-		DeviceMAC:   device.MAC().String(),
+		DeviceMAC:   device.MAC.String(),
+		DeviceName:  device.Name,
+		DeviceModel: device.Model,
 		ValidTLVs:   make([]TLVResponse, 0),
-		TotalTested: int(end - start + 1),
+		TotalTested: int(end-start) + 1,
 	}
 
 	startTime := time.Now()
 
-	// Get basic device info
-	if name, err := device.GetName(timeout); err == nil {
-		results.DeviceName = name
-	}
-	if model, err := device.GetModel(timeout); err == nil {
-		results.DeviceModel = model
-	}
-
 	fmt.Printf("Device MAC: %s\n", results.DeviceMAC)
 	if results.DeviceName != "" {
 		fmt.Printf("Device Name: %s\n", results.DeviceName)
@@ -147,34 +125,34 @@ func scanDevice(device *nsdp.Device, iface *net.Interface, start, end uint16, ba
 	}
 	fmt.Println()
 
-	// Scan TLVs in batches
-	current := start
+	// Scan TLVs in batches (int counters, as 0xFFFF+1 would wrap a uint16)
+	current := int(start)
 	batchNum := 1
-	
-	for current <= end {
-		batchEnd := current + uint16(batchSize) - 1
-		if batchEnd > end {
-			batchEnd = end
+
+	for current <= int(end) {
+		batchEnd := current + batchSize - 1
+		if batchEnd > int(end) {
+			batchEnd = int(end)
 		}
-		
+
 		fmt.Printf("Scanning batch %d: 0x%04X to 0x%04X...", batchNum, current, batchEnd)
-		
-		batchResults := scanBatch(device, current, batchEnd, timeout, verbose)
+
+		batchResults := scanBatch(client, device.MAC, uint16(current), uint16(batchEnd), verbose)
 		results.ValidTLVs = append(results.ValidTLVs, batchResults...)
-		
+
 		fmt.Printf(" Found %d valid TLVs\n", len(batchResults))
-		
+
 		if verbose && len(batchResults) > 0 {
 			for _, tlv := range batchResults {
 				fmt.Printf("  0x%04X: %d bytes - %s\n", tlv.TLV, tlv.Length, tlv.HexValue)
 			}
 		}
-		
+
 		current = batchEnd + 1
 		batchNum++
-		
+
 		// Add delay between batches to avoid overwhelming the device
-		if current <= end && delay > 0 {
+		if current <= int(end) && delay > 0 {
 			time.Sleep(delay)
 		}
 	}
@@ -185,16 +163,17 @@ func scanDevice(device *nsdp.Device, iface *net.Interface, start, end uint16, ba
 	return results
 }
 
-func scanBatch(device *nsdp.Device, start, end uint16, timeout time.Duration, verbose bool) []TLVResponse {
+func scanBatch(client *nsdpclient.Client, deviceMAC net.HardwareAddr, start, end uint16, verbose bool) []TLVResponse {
 	var results []TLVResponse
 
-	for tlv := start; tlv <= end; tlv++ {
+	for code := int(start); code <= int(end); code++ {
+		tlv := uint16(code)
 		if verbose && tlv%1000 == 0 {
 			fmt.Printf("  Testing 0x%04X...\n", tlv)
 		}
 
 		// Try to query this TLV
-		response, err := queryTLV(device, tlv, timeout)
+		response, err := client.ReadParam(deviceMAC, tlv)
 		if err != nil {
 			if verbose && tlv%1000 == 0 {
 				fmt.Printf("  0x%04X: Error - %v\n", tlv, err)
@@ -210,7 +189,7 @@ func scanBatch(device *nsdp.Device, start, end uint16, timeout time.Duration, ve
 				Length:   len(response),
 			}
 			results = append(results, tlvResp)
-			
+
 			if verbose {
 				fmt.Printf("  0x%04X: SUCCESS - %d bytes: %s\n", tlv, len(response), tlvResp.HexValue)
 			}
@@ -220,25 +199,6 @@ func scanBatch(device *nsdp.Device, start, end uint16, timeout time.Duration, ve
 	return results
 }
 
-func queryTLV(device *nsdp.Device, tlv uint16, timeout time.Duration) ([]byte, error) {
-	// Create a custom query for this TLV
-	// We'll use the device's Query method with a custom TLV
-	query := nsdp.NewQuery()
-	query.Add(nsdp.TLV(tlv), nil) // Query with empty value to request the parameter
-	
-	response, err := device.Query(query, timeout)
-	if err != nil {
-		return nil, err
-	}
-
-	// Extract the response data for our TLV
-	if data, exists := response.Get(nsdp.TLV(tlv)); exists {
-		return data, nil
-	}
-
-	return nil, fmt.Errorf("TLV not in response")
-}
-
 func displayResults(results DiscoveryResults) {
 	fmt.Printf("=== Scan Results ===\n")
 	fmt.Printf("Total TLVs tested: %d\n", results.TotalTested)
@@ -249,16 +209,21 @@ func displayResults(results DiscoveryResults) {
 
 	if len(results.ValidTLVs) > 0 {
 		fmt.Printf("=== Valid TLVs ===\n")
-		
+
 		// Sort by TLV value
 		sort.Slice(results.ValidTLVs, func(i, j int) bool {
 			return results.ValidTLVs[i].TLV < results.ValidTLVs[j].TLV
 		})
-		
+
 		for _, tlv := range results.ValidTLVs {
-			fmt.Printf("0x%04X (%5d): %3d bytes - %s\n", 
+			fmt.Printf("0x%04X (%5d): %3d bytes - %s\n",
 				tlv.TLV, tlv.TLV, tlv.Length, tlv.HexValue)
-			
+
+			// Name the parameter if it is known for this model
+			for _, known := range nsdpclient.Interpretations(tlv.TLV, "", results.DeviceModel) {
+				fmt.Printf("                   Known as: %s\n", known.Description)
+			}
+
 			// Try to interpret common data types
 			if interpretation := interpretTLVData(tlv); interpretation != "" {
 				fmt.Printf("                   Interpretation: %s\n", interpretation)
@@ -290,14 +255,14 @@ func interpretTLVData(tlv TLVResponse) string {
 	case 4:
 		val := uint32(data[0])<<24 | uint32(data[1])<<16 | uint32(data[2])<<8 | uint32(data[3])
 		interpretations = append(interpretations, fmt.Sprintf("Uint32: %d", val))
-		
+
 		// Try as IP address
 		if len(data) == 4 {
 			interpretations = append(interpretations, fmt.Sprintf("IP: %d.%d.%d.%d", data[0], data[1], data[2], data[3]))
 		}
 	case 6:
 		// Try as MAC address
-		interpretations = append(interpretations, fmt.Sprintf("MAC: %02x:%02x:%02x:%02x:%02x:%02x", 
+		interpretations = append(interpretations, fmt.Sprintf("MAC: %02x:%02x:%02x:%02x:%02x:%02x",
 			data[0], data[1], data[2], data[3], data[4], data[5]))
 	}
 
@@ -345,12 +310,12 @@ func saveResults(results DiscoveryResults, filename string) {
 	// Write TLV data
 	fmt.Fprintf(file, "Valid TLVs:\n")
 	fmt.Fprintf(file, "-----------\n")
-	
+
 	for _, tlv := range results.ValidTLVs {
 		fmt.Fprintf(file, "TLV: 0x%04X (%d)\n", tlv.TLV, tlv.TLV)
 		fmt.Fprintf(file, "Length: %d bytes\n", tlv.Length)
 		fmt.Fprintf(file, "Hex Data: %s\n", tlv.HexValue)
-		
+
 		if interpretation := interpretTLVData(tlv); interpretation != "" {
 			fmt.Fprintf(file, "Interpretation: %s\n", interpretation)
 		}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+func runSet(g *globalOptions, args []string) error {
+	fs := newFlagSet("set", g)
+	name := fs.String("name", "", "New device name")
+	location := fs.String("location", "", "New device location")
+	ip := fs.String("ip", "", "New static IP address")
+	netmask := fs.String("netmask", "", "New netmask")
+	gateway := fs.String("gateway", "", "New gateway")
+	dhcp := fs.String("dhcp", "", "DHCP mode: enabled or disabled")
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+	fs.Parse(args)
+
+	settings, err := parseSettings(fs, *name, *location, *ip, *netmask, *gateway, *dhcp)
+	if err != nil {
+		return err
+	}
+	if settings.IsEmpty() {
+		return fmt.Errorf("nothing to change (use -name, -location, -ip, -netmask, -gateway or -dhcp)")
+	}
+
+	return changeDevice(g, *yes, changeSteps[*nsdpclient.DeviceInfo]{
+		title: "NSDP Device Settings",
+		what:  "current settings",
+		read: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) (*nsdpclient.DeviceInfo, error) {
+			return client.DeviceInfo(device.MAC)
+		},
+		validate: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, current *nsdpclient.DeviceInfo) error {
+			return settings.Validate(current)
+		},
+		print: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, current *nsdpclient.DeviceInfo) bool {
+			printSettingsChange(current, settings)
+			return true
+		},
+		apply: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, password string, _ *nsdpclient.DeviceInfo) error {
+			return client.ApplySettings(device.MAC, password, settings)
+		},
+		reread: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) (*nsdpclient.DeviceInfo, error) {
+			ip := verificationAddress(g.resolvedTarget, settings)
+			if ip == nil {
+				return client.DeviceInfo(device.MAC)
+			}
+			verifier, err := nsdpclient.New(nsdpclient.TargetAddress(ip), g.timeout, g.verbose)
+			if err != nil {
+				return nil, err
+			}
+			defer verifier.Close()
+			fmt.Printf("Verifying on the new address %s\n", ip)
+			return verifier.DeviceInfo(device.MAC)
+		},
+		mismatches: settings.Mismatches,
+	})
+}
+
+// verificationAddress returns the address to read the settings back from
+// when the switch is addressed by IP and the change moves it to another one,
+// or nil if the client used for the change can verify it.
+func verificationAddress(t *target, settings nsdpclient.DeviceSettings) net.IP {
+	if t == nil || t.ip == nil || settings.IP == nil || settings.IP.Equal(t.ip) {
+		return nil
+	}
+	return settings.IP
+}
+
+// parseSettings collects the settings given on the command line. Only flags
+// actually set are changed, so a location can be cleared with -location "".
+func parseSettings(fs *flag.FlagSet, name, location, ip, netmask, gateway, dhcp string) (nsdpclient.DeviceSettings, error) {
+	var settings nsdpclient.DeviceSettings
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		switch f.Name {
+		case "name":
+			settings.Name = &name
+		case "location":
+			settings.Location = &location
+		case "ip":
+			settings.IP, err = parseIPv4("IP address", ip)
+		case "netmask":
+			settings.Netmask, err = parseIPv4("netmask", netmask)
+		case "gateway":
+			settings.Gateway, err = parseIPv4("gateway", gateway)
+		case "dhcp":
+			var mode uint8
+			switch dhcp {
+			case "enabled":
+				mode = nsdpclient.DHCPEnabled
+			case "disabled":
+				mode = nsdpclient.DHCPDisabled
+			default:
+				err = fmt.Errorf("invalid DHCP mode %q (enabled or disabled)", dhcp)
+			}
+			settings.DHCPMode = &mode
+		}
+	})
+	return settings, err
+}
+
+func parseIPv4(name, value string) (net.IP, error) {
+	ip := net.ParseIP(value)
+	if ip == nil || ip.To4() == nil {
+		return nil, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return ip.To4(), nil
+}
+
+// printSettingsChange prints the current and new value of each setting to
+// change.
+func printSettingsChange(current *nsdpclient.DeviceInfo, settings nsdpclient.DeviceSettings) {
+	fmt.Printf("%-12s %-20s %s\n", "Setting", "Current", "New")
+	if settings.Name != nil {
+		fmt.Printf("%-12s %-20s %s\n", "Name", current.Name, *settings.Name)
+	}
+	if settings.Location != nil {
+		fmt.Printf("%-12s %-20s %s\n", "Location", current.Location, *settings.Location)
+	}
+	if settings.IP != nil {
+		fmt.Printf("%-12s %-20s %s\n", "IP Address", current.IP, settings.IP)
+	}
+	if settings.Netmask != nil {
+		fmt.Printf("%-12s %-20s %s\n", "Netmask", current.Netmask, settings.Netmask)
+	}
+	if settings.Gateway != nil {
+		fmt.Printf("%-12s %-20s %s\n", "Gateway", current.Gateway, settings.Gateway)
+	}
+	if settings.DHCPMode != nil {
+		currentMode := "-"
+		if current.DHCPMode != nil {
+			currentMode = formatDHCPMode(*current.DHCPMode)
+		}
+		fmt.Printf("%-12s %-20s %s\n", "DHCP", currentMode, formatDHCPMode(*settings.DHCPMode))
+	}
+}
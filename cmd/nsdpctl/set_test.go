@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"net"
+	"testing"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+func TestParseSettings(t *testing.T) {
+	fs := flag.NewFlagSet("set", flag.ContinueOnError)
+	name := fs.String("name", "", "")
+	location := fs.String("location", "", "")
+	ip := fs.String("ip", "", "")
+	dhcp := fs.String("dhcp", "", "")
+	if err := fs.Parse([]string{"-location", "", "-ip", "192.168.1.10", "-dhcp", "disabled"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	settings, err := parseSettings(fs, *name, *location, *ip, "", "", *dhcp)
+	if err != nil {
+		t.Fatalf("parseSettings failed: %v", err)
+	}
+	if settings.Name != nil {
+		t.Error("Expected name to stay unchanged")
+	}
+	if settings.Location == nil || *settings.Location != "" {
+		t.Error("Expected location to be cleared")
+	}
+	if settings.IP.String() != "192.168.1.10" {
+		t.Errorf("Unexpected IP: %s", settings.IP)
+	}
+	if settings.DHCPMode == nil || *settings.DHCPMode != nsdpclient.DHCPDisabled {
+		t.Error("Expected DHCP to be disabled")
+	}
+
+	fs = flag.NewFlagSet("set", flag.ContinueOnError)
+	ip = fs.String("ip", "", "")
+	fs.Parse([]string{"-ip", "192.168.1.300"})
+	if _, err := parseSettings(fs, "", "", *ip, "", "", ""); err == nil {
+		t.Error("Expected error for invalid IP address")
+	}
+}
+
+func TestVerificationAddress(t *testing.T) {
+	newIP := net.ParseIP("10.1.0.20").To4()
+	byIP := &target{spec: "10.1.0.3", ip: net.ParseIP("10.1.0.3")}
+	byMAC := &target{spec: "6c:b0:ce:1c:83:94", mac: net.HardwareAddr{0x6c, 0xb0, 0xce, 0x1c, 0x83, 0x94}}
+
+	if ip := verificationAddress(byIP, nsdpclient.DeviceSettings{IP: newIP}); !ip.Equal(newIP) {
+		t.Errorf("Expected verification on %s, got %v", newIP, ip)
+	}
+	if ip := verificationAddress(byIP, nsdpclient.DeviceSettings{IP: net.ParseIP("10.1.0.3")}); ip != nil {
+		t.Errorf("Expected no new address for an unchanged IP, got %s", ip)
+	}
+	if ip := verificationAddress(byMAC, nsdpclient.DeviceSettings{IP: newIP}); ip != nil {
+		t.Errorf("Expected broadcast verification for a MAC target, got %s", ip)
+	}
+	if ip := verificationAddress(nil, nsdpclient.DeviceSettings{IP: newIP}); ip != nil {
+		t.Errorf("Expected broadcast verification without a target, got %s", ip)
+	}
+}
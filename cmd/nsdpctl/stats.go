@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hdecarne-github/go-nsdp"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+func runStats(g *globalOptions, args []string) error {
+	fs := newFlagSet("stats", g)
+	fs.Parse(args)
+
+	switch {
+	case g.output == "json":
+		return g.reportDevices(sectionStatistics)
+	case g.tabular():
+		return g.tabulateDevices(portStatsTable, sectionStatistics)
+	}
+
+	return forEachDevice(g, "NSDP Port Statistics", func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) {
+		queryDeviceStatistics(client, device, g.verbose)
+	})
+}
+
+func queryDeviceStatistics(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, verbose bool) {
+	if device.MAC == nil {
+		if verbose {
+			fmt.Println("Cannot query device details: no MAC address found")
+		}
+		return
+	}
+
+	fmt.Println("--- Port Information ---")
+
+	report, err := client.Ports(device.MAC)
+	if err != nil {
+		if verbose {
+			fmt.Printf("Error querying statistics - %v\n", err)
+		}
+		return
+	}
+	printPortStatisticsReport(report, verbose)
+}
+
+// printPortStatisticsReport prints the counters of every port of a device.
+func printPortStatisticsReport(report *nsdpclient.PortReport, verbose bool) {
+	for p := 1; p <= report.Count(); p++ {
+		portStat := report.StatisticOf(uint8(p))
+		if portStat == nil {
+			if verbose {
+				fmt.Printf("Port %d: No statistics available\n", p)
+			}
+			continue
+		}
+		printPortStatistic(portStat)
+	}
+}
+
+// printPortStatistic prints the counters of a port. All commands use the
+// field names of nsdp.PortStatistic.
+func printPortStatistic(portStat *nsdp.PortStatistic) {
+	fmt.Printf("Port %d Statistics:\n", portStat.Port)
+	fmt.Printf("  RX Bytes: %d\n", portStat.Received)
+	fmt.Printf("  TX Bytes: %d\n", portStat.Sent)
+	fmt.Printf("  Packets: %d\n", portStat.Packets)
+	fmt.Printf("  Broadcasts: %d\n", portStat.Broadcasts)
+	fmt.Printf("  Multicasts: %d\n", portStat.Multicasts)
+	fmt.Printf("  Errors: %d\n", portStat.Errors)
+}
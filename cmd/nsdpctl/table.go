@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+// tableLayout selects the rows written with -o csv and -o table.
+type tableLayout int
+
+const (
+	deviceTable     tableLayout = iota // One row per device
+	portStatusTable                    // One row per port with its link status
+	portStatsTable                     // One row per port with its counters
+)
+
+// table holds rows of device report values. Columns are named in lower
+// case; the aligned table prints them in upper case.
+type table struct {
+	columns []string
+	rows    [][]string
+}
+
+// tabular reports whether the output format is one of the row based ones.
+func (g *globalOptions) tabular() bool {
+	return g.output == "csv" || g.output == "table"
+}
+
+// tabulateDevices discovers the devices and writes the rows of the layout
+// for all of them. Sections that could not be read are listed on stderr.
+func (g *globalOptions) tabulateDevices(layout tableLayout, sections reportSection, extra ...nsdp.TLV) error {
+	reports, err := g.collectReports(sections, extra...)
+	if err != nil {
+		return err
+	}
+	for _, report := range reports {
+		for _, e := range report.Errors {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", report.Identity.MAC, e)
+		}
+	}
+	return writeTable(os.Stdout, g.output, newTable(layout, reports))
+}
+
+func newTable(layout tableLayout, reports []*deviceReport) *table {
+	switch layout {
+	case portStatusTable:
+		t := &table{columns: []string{"mac", "name", "port", "link", "speed_mbps", "duplex"}}
+		for _, r := range reports {
+			for _, p := range r.Ports {
+				if p.Link == nil {
+					continue
+				}
+				t.rows = append(t.rows, append(portColumns(r, p),
+					formatLink(p.Link.Up), formatOptionalInt(p.Link.SpeedMbps), formatDuplex(p.Link)))
+			}
+		}
+		return t
+	case portStatsTable:
+		t := &table{columns: []string{"mac", "name", "port", "rx_bytes", "tx_bytes", "packets", "broadcasts", "multicasts", "errors"}}
+		for _, r := range reports {
+			for _, p := range r.Ports {
+				if s := p.Statistics; s != nil {
+					t.rows = append(t.rows, append(portColumns(r, p),
+						formatCounter(s.RXBytes), formatCounter(s.TXBytes), formatCounter(s.Packets),
+						formatCounter(s.Broadcasts), formatCounter(s.Multicasts), formatCounter(s.Errors)))
+				}
+			}
+		}
+		return t
+	default:
+		t := &table{columns: []string{"mac", "name", "model", "ip", "dhcp", "boot_firmware", "ports"}}
+		for _, r := range reports {
+			t.rows = append(t.rows, deviceColumns(r))
+		}
+		return t
+	}
+}
+
+func deviceColumns(r *deviceReport) []string {
+	var ip, dhcp, firmware string
+	if r.Network != nil {
+		ip = r.Network.IP
+		if r.Network.DHCP != nil {
+			dhcp = formatEnabled(*r.Network.DHCP)
+		}
+	}
+	if r.Firmware != nil {
+		firmware = r.Firmware.BootVersion
+	}
+	ports := r.PortCount
+	if ports == 0 {
+		ports = len(r.Ports)
+	}
+	return []string{r.Identity.MAC, r.Identity.Name, r.Identity.Model, ip, dhcp, firmware, formatOptionalInt(ports)}
+}
+
+func portColumns(r *deviceReport, p *portReport) []string {
+	return []string{r.Identity.MAC, r.Identity.Name, strconv.Itoa(int(p.Port))}
+}
+
+func formatLink(up bool) string {
+	if up {
+		return "up"
+	}
+	return "down"
+}
+
+func formatDuplex(link *linkReport) string {
+	switch {
+	case link.SpeedMbps == 0:
+		return ""
+	case link.FullDuplex:
+		return "full"
+	default:
+		return "half"
+	}
+}
+
+// formatOptionalInt leaves zero values empty, as they mean not reported.
+func formatOptionalInt(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+func formatCounter(value uint64) string {
+	return strconv.FormatUint(value, 10)
+}
+
+// writeTable writes the rows as CSV with a header line, or as columns
+// aligned for the terminal.
+func writeTable(w io.Writer, format string, t *table) error {
+	if format == "csv" {
+		out := csv.NewWriter(w)
+		out.Write(t.columns)
+		out.WriteAll(t.rows)
+		return out.Error()
+	}
+
+	out := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(out, strings.ToUpper(strings.Join(t.columns, "\t")))
+	for _, row := range t.rows {
+		fmt.Fprintln(out, strings.Join(row, "\t"))
+	}
+	return out.Flush()
+}
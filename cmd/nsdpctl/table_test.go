@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func testReports() []*deviceReport {
+	dhcp := false
+	return []*deviceReport{{
+		Identity:  identityReport{MAC: "6c:b0:ce:1c:83:94", Name: "switch1", Model: "GS108Ev3"},
+		Network:   &networkReport{IP: "10.1.0.3", DHCP: &dhcp},
+		Firmware:  &firmwareReport{Slots: []firmwareSlotReport{{Slot: 1, Version: "2.06.17"}, {Slot: 2, Version: "2.06.10"}}, NextSlot: 1, BootVersion: "2.06.17"},
+		PortCount: 8,
+		Ports: []*portReport{
+			{Port: 1, Link: newLinkReport(0x05), Statistics: &statisticsReport{RXBytes: 1024, Errors: 2}},
+			{Port: 2, Link: newLinkReport(0x00)},
+		},
+	}, {
+		Identity: identityReport{MAC: "00:11:22:33:44:55", Model: "GS105E"},
+	}}
+}
+
+func TestNewTable(t *testing.T) {
+	devices := newTable(deviceTable, testReports())
+	if len(devices.rows) != 2 || !reflect.DeepEqual(devices.rows[0], []string{"6c:b0:ce:1c:83:94", "switch1", "GS108Ev3", "10.1.0.3", "Disabled", "2.06.17", "8"}) {
+		t.Errorf("Unexpected device rows: %q", devices.rows)
+	}
+
+	status := newTable(portStatusTable, testReports())
+	if !reflect.DeepEqual(status.rows, [][]string{
+		{"6c:b0:ce:1c:83:94", "switch1", "1", "up", "1000", "full"},
+		{"6c:b0:ce:1c:83:94", "switch1", "2", "down", "", ""},
+	}) {
+		t.Errorf("Unexpected port status rows: %q", status.rows)
+	}
+
+	stats := newTable(portStatsTable, testReports())
+	if len(stats.rows) != 1 || len(stats.rows[0]) != len(stats.columns) || stats.rows[0][3] != "1024" {
+		t.Errorf("Unexpected statistics rows: %q", stats.rows)
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	tbl := &table{columns: []string{"mac", "name"}, rows: [][]string{{"00:11:22:33:44:55", "lab, rack 2"}}}
+
+	var buf bytes.Buffer
+	if err := writeTable(&buf, "csv", tbl); err != nil {
+		t.Fatalf("writeTable failed: %v", err)
+	}
+	if expected := "mac,name\n00:11:22:33:44:55,\"lab, rack 2\"\n"; buf.String() != expected {
+		t.Errorf("Unexpected CSV:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeTable(&buf, "table", tbl); err != nil {
+		t.Fatalf("writeTable failed: %v", err)
+	}
+	if expected := "MAC                NAME\n00:11:22:33:44:55  lab, rack 2\n"; buf.String() != expected {
+		t.Errorf("Unexpected table:\n%s", buf.String())
+	}
+}
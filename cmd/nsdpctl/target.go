@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// target is a single switch selected with -target.
+type target struct {
+	spec string           // As given on the command line
+	ip   net.IP           // Set if the switch is addressed by IP (unicast)
+	mac  net.HardwareAddr // Set if the switch is addressed by MAC (broadcast)
+}
+
+func (t *target) String() string {
+	if t.ip != nil {
+		if t.spec != t.ip.String() {
+			return fmt.Sprintf("%s (%s)", t.spec, t.ip)
+		}
+		return t.spec
+	}
+	if t.spec != t.mac.String() {
+		return fmt.Sprintf("%s (%s)", t.spec, t.mac)
+	}
+	return t.spec
+}
+
+// defaultInventoryPath returns the inventory file used if -inventory is not
+// given.
+func defaultInventoryPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "nsdpctl", "inventory")
+}
+
+// resolveTarget parses a -target value: an IP address, a MAC address or a
+// name listed in the inventory file.
+func resolveTarget(spec, inventoryPath string) (*target, error) {
+	if address, err := parseTargetAddress(spec); err == nil {
+		address.spec = spec
+		return address, nil
+	}
+
+	inventory, err := loadInventory(inventoryPath)
+	if err != nil {
+		return nil, err
+	}
+	address, ok := inventory[spec]
+	if !ok {
+		return nil, fmt.Errorf("target %q is neither an IP or MAC address nor listed in the inventory %s", spec, inventoryPath)
+	}
+	resolved, err := parseTargetAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("inventory entry %q: %w", spec, err)
+	}
+	resolved.spec = spec
+	return resolved, nil
+}
+
+func parseTargetAddress(address string) (*target, error) {
+	if ip := net.ParseIP(address); ip != nil {
+		return &target{ip: ip}, nil
+	}
+	if mac, err := net.ParseMAC(address); err == nil {
+		return &target{mac: mac}, nil
+	}
+	return nil, fmt.Errorf("invalid IP or MAC address %q", address)
+}
+
+// loadInventory reads an inventory file mapping switch names to IP or MAC
+// addresses. Each line holds a name and an address separated by whitespace;
+// blank lines and lines starting with # are ignored.
+func loadInventory(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no inventory file (-inventory)")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open inventory: %w", err)
+	}
+	defer file.Close()
+
+	inventory := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected \"<name> <address>\"", path, lineNo)
+		}
+		inventory[fields[0]] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read inventory: %w", err)
+	}
+	return inventory, nil
+}
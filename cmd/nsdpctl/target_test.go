@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveTarget(t *testing.T) {
+	inventory := filepath.Join(t.TempDir(), "inventory")
+	content := "# name address\ncore-sw1 10.1.0.3\n\nlab-sw2  6c:b0:ce:1c:83:94\nbroken nonsense\n"
+	if err := os.WriteFile(inventory, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write inventory: %v", err)
+	}
+
+	tests := []struct {
+		spec string
+		ip   string
+		mac  string
+	}{
+		{spec: "192.168.1.100", ip: "192.168.1.100"},
+		{spec: "00:11:22:33:44:55", mac: "00:11:22:33:44:55"},
+		{spec: "core-sw1", ip: "10.1.0.3"},
+		{spec: "lab-sw2", mac: "6c:b0:ce:1c:83:94"},
+	}
+	for _, tt := range tests {
+		resolved, err := resolveTarget(tt.spec, inventory)
+		if err != nil {
+			t.Errorf("%s: %v", tt.spec, err)
+			continue
+		}
+		if tt.ip != "" && (resolved.ip == nil || resolved.ip.String() != tt.ip) {
+			t.Errorf("%s: expected IP %s, got %v", tt.spec, tt.ip, resolved)
+		}
+		if tt.mac != "" && (resolved.mac == nil || resolved.mac.String() != tt.mac) {
+			t.Errorf("%s: expected MAC %s, got %v", tt.spec, tt.mac, resolved)
+		}
+	}
+
+	for _, spec := range []string{"unknown-sw", "broken"} {
+		if _, err := resolveTarget(spec, inventory); err == nil {
+			t.Errorf("Expected error for target %q", spec)
+		}
+	}
+}
+
+func TestLoadInventoryInvalid(t *testing.T) {
+	inventory := filepath.Join(t.TempDir(), "inventory")
+	if err := os.WriteFile(inventory, []byte("core-sw1\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write inventory: %v", err)
+	}
+	if _, err := loadInventory(inventory); err == nil {
+		t.Error("Expected error for line without address")
+	}
+}
@@ -0,0 +1,512 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+// VLAN subcommands changing the configuration
+var vlanSubcommands = map[string]func(g *globalOptions, args []string) error{
+	"add":     runVLANAdd,
+	"del":     runVLANDel,
+	"members": runVLANMembers,
+	"pvid":    runVLANPVID,
+	"engine":  runVLANEngine,
+}
+
+func runVLAN(g *globalOptions, args []string) error {
+	// Global flags may precede the subcommand
+	fs := newFlagSet("vlan", g)
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		if sub, ok := vlanSubcommands[fs.Arg(0)]; ok {
+			return sub(g, fs.Args()[1:])
+		}
+		return fmt.Errorf("unknown subcommand %q (add, del, members, pvid or engine)", fs.Arg(0))
+	}
+
+	if g.output == "json" {
+		return g.reportDevices(sectionVLANs)
+	}
+
+	return forEachDevice(g, "NSDP VLAN Configuration", func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) {
+		queryVLANConfiguration(client, device.MAC, g.verbose)
+	})
+}
+
+func queryVLANConfiguration(client *nsdpclient.Client, deviceMAC net.HardwareAddr, verbose bool) {
+	fmt.Println("\n--- VLAN Configuration ---")
+
+	// Query VLAN engine mode
+	result := queryCustomParameter(client, deviceMAC, nsdpclient.ParamVLANEngine, verbose)
+	if result != nil && len(result) >= 1 {
+		mode := result[0]
+		fmt.Printf("VLAN Engine: %s\n", formatVLANEngineMode(mode))
+	}
+
+	// Query VLAN membership information
+	var memberships []nsdpclient.VLANMembership
+	records := queryCustomParameterRecords(client, deviceMAC, nsdpclient.ParamVLAN8021Q, verbose)
+	if records != nil {
+		printRawRecords(records, verbose)
+		decoded, err := nsdpclient.DecodeVLAN8021Q(records)
+		if err != nil {
+			fmt.Printf("802.1Q VLAN Data: %v\n", err)
+		} else {
+			memberships = decoded
+			printVLANMemberships(memberships)
+			printVLANMatrix(memberships, queryPortCount(client, deviceMAC, verbose))
+		}
+	}
+
+	// Query PVID information
+	records = queryCustomParameterRecords(client, deviceMAC, nsdpclient.ParamVLANPVID, verbose)
+	if records != nil {
+		printRawRecords(records, verbose)
+		pvids, err := nsdpclient.DecodeVLANPVID(records)
+		if err != nil {
+			fmt.Printf("PVID Data: %v\n", err)
+		} else {
+			printPVIDTable(pvids, memberships)
+		}
+	}
+}
+
+// printPVIDTable prints the default VLAN of each port. If the VLAN
+// membership is known, ports whose PVID is not an untagged member of that
+// VLAN are flagged.
+func printPVIDTable(pvids []nsdpclient.PortPVID, memberships []nsdpclient.VLANMembership) {
+	var issues []nsdpclient.PVIDIssue
+	if memberships != nil {
+		issues = nsdpclient.CheckPVIDs(pvids, memberships)
+	}
+
+	fmt.Println("\nPort  PVID")
+	for _, pvid := range pvids {
+		line := fmt.Sprintf("%-5d %-5d", pvid.Port, pvid.VLAN)
+		for _, issue := range issues {
+			if issue.Port == pvid.Port {
+				line += " WARNING: " + issue.Problem
+			}
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	if len(issues) > 0 {
+		fmt.Printf("\n%d port(s) have a PVID that is not an untagged member of its VLAN\n", len(issues))
+	}
+}
+
+func printVLANMemberships(memberships []nsdpclient.VLANMembership) {
+	for _, m := range memberships {
+		fmt.Printf("VLAN %d: Tagged: %s, Untagged: %s\n", m.ID, formatPortList(m.Tagged), formatPortList(m.Untagged()))
+	}
+}
+
+// printVLANMatrix prints a port-by-VLAN table of the memberships. The table
+// covers portCount ports, or more if a VLAN lists a higher port.
+func printVLANMatrix(memberships []nsdpclient.VLANMembership, portCount int) {
+	for _, m := range memberships {
+		for _, port := range m.Members {
+			if int(port) > portCount {
+				portCount = int(port)
+			}
+		}
+	}
+	if portCount == 0 {
+		return
+	}
+
+	fmt.Println("\nVLAN Matrix (U = untagged, T = tagged, - = not a member):")
+	var header strings.Builder
+	header.WriteString("VLAN ")
+	for port := 1; port <= portCount; port++ {
+		fmt.Fprintf(&header, " %2d", port)
+	}
+	fmt.Println(header.String())
+
+	for _, m := range memberships {
+		var row strings.Builder
+		fmt.Fprintf(&row, "%-5d", m.ID)
+		for p := 1; p <= portCount; p++ {
+			port := uint8(p)
+			mark := "-"
+			if m.IsTagged(port) {
+				mark = "T"
+			} else if m.IsMember(port) {
+				mark = "U"
+			}
+			fmt.Fprintf(&row, " %2s", mark)
+		}
+		fmt.Println(row.String())
+	}
+}
+
+// vlanChangeOptions are the flags shared by the VLAN subcommands that change
+// the configuration.
+type vlanChangeOptions struct {
+	yes      bool
+	force    bool
+	uplink   uint
+	mgmtVLAN uint
+}
+
+func newVLANChangeFlagSet(name string, g *globalOptions, opts *vlanChangeOptions) *flag.FlagSet {
+	fs := newFlagSet("vlan "+name, g)
+	fs.BoolVar(&opts.yes, "yes", false, "Apply without asking for confirmation")
+	fs.BoolVar(&opts.force, "force", false, "Apply even if management access may be lost")
+	fs.UintVar(&opts.uplink, "uplink", 0, "Port this host reaches the switch through (required to change the management VLAN)")
+	fs.UintVar(&opts.mgmtVLAN, "mgmt-vlan", 1, "Management VLAN of the switch")
+	return fs
+}
+
+func parseVLANID(id uint) (uint16, error) {
+	if id < 1 || id > 4093 {
+		return 0, fmt.Errorf("a VLAN ID between 1 and 4093 is required (-id)")
+	}
+	return uint16(id), nil
+}
+
+func runVLANAdd(g *globalOptions, args []string) error {
+	return runVLANMembershipChange(g, "add", args)
+}
+
+func runVLANMembers(g *globalOptions, args []string) error {
+	return runVLANMembershipChange(g, "members", args)
+}
+
+// runVLANMembershipChange creates a VLAN (add) or replaces the member ports
+// of an existing one (members).
+func runVLANMembershipChange(g *globalOptions, name string, args []string) error {
+	var opts vlanChangeOptions
+	fs := newVLANChangeFlagSet(name, g, &opts)
+	id := fs.Uint("id", 0, "VLAN ID (required)")
+	untaggedList := fs.String("untagged", "", "Untagged member ports, e.g. 1-4,7")
+	taggedList := fs.String("tagged", "", "Tagged member ports, e.g. 8")
+	fs.Parse(args)
+
+	vlanID, err := parseVLANID(*id)
+	if err != nil {
+		return err
+	}
+	untagged, err := parsePortList(*untaggedList)
+	if err != nil {
+		return err
+	}
+	tagged, err := parsePortList(*taggedList)
+	if err != nil {
+		return err
+	}
+	for _, port := range tagged {
+		if containsPort(untagged, port) {
+			return fmt.Errorf("port %d cannot be both tagged and untagged", port)
+		}
+	}
+	membership := nsdpclient.VLANMembership{ID: vlanID, Members: sortedPorts(append(untagged, tagged...)), Tagged: sortedPorts(tagged)}
+
+	return changeVLANConfig(g, opts, func(client *nsdpclient.Client, mac net.HardwareAddr, config *nsdpclient.VLANConfig) (vlanChange, error) {
+		existing := config.Membership(vlanID)
+		if name == "add" && existing != nil {
+			return vlanChange{}, fmt.Errorf("VLAN %d already exists (use vlan members to change it)", vlanID)
+		}
+		if name == "members" && existing == nil {
+			return vlanChange{}, fmt.Errorf("VLAN %d does not exist (use vlan add to create it)", vlanID)
+		}
+
+		width := config.BitmapWidth
+		if width == 0 {
+			width = nsdpclient.PortBitmapWidth(queryPortCount(client, mac, g.verbose))
+		}
+		if width == 0 {
+			return vlanChange{}, fmt.Errorf("unknown port count")
+		}
+
+		description := fmt.Sprintf("VLAN %d: Tagged: %s, Untagged: %s", vlanID, formatPortList(membership.Tagged), formatPortList(membership.Untagged()))
+		if existing != nil {
+			description = fmt.Sprintf("VLAN %d: Tagged: %s, Untagged: %s -> Tagged: %s, Untagged: %s", vlanID,
+				formatPortList(existing.Tagged), formatPortList(existing.Untagged()),
+				formatPortList(membership.Tagged), formatPortList(membership.Untagged()))
+		}
+		return vlanChange{
+			description: description,
+			apply: func(config *nsdpclient.VLANConfig) {
+				if existing := config.Membership(vlanID); existing != nil {
+					*existing = membership
+				} else {
+					config.Memberships = append(config.Memberships, membership)
+				}
+			},
+			write: func(password string) error {
+				return client.SetVLANMembership(mac, password, membership, width)
+			},
+			mismatches: func(config *nsdpclient.VLANConfig) []string {
+				if m := config.Membership(vlanID); m == nil || !equalPorts(m.Members, membership.Members) || !equalPorts(m.Tagged, membership.Tagged) {
+					return []string{fmt.Sprintf("VLAN %d members not changed", vlanID)}
+				}
+				return nil
+			},
+		}, nil
+	})
+}
+
+func runVLANDel(g *globalOptions, args []string) error {
+	var opts vlanChangeOptions
+	fs := newVLANChangeFlagSet("del", g, &opts)
+	id := fs.Uint("id", 0, "VLAN ID (required)")
+	fs.Parse(args)
+
+	vlanID, err := parseVLANID(*id)
+	if err != nil {
+		return err
+	}
+
+	return changeVLANConfig(g, opts, func(client *nsdpclient.Client, mac net.HardwareAddr, config *nsdpclient.VLANConfig) (vlanChange, error) {
+		if config.Membership(vlanID) == nil {
+			return vlanChange{}, fmt.Errorf("VLAN %d does not exist", vlanID)
+		}
+		return vlanChange{
+			description: fmt.Sprintf("Delete VLAN %d", vlanID),
+			apply: func(config *nsdpclient.VLANConfig) {
+				for i, m := range config.Memberships {
+					if m.ID == vlanID {
+						config.Memberships = append(config.Memberships[:i], config.Memberships[i+1:]...)
+						break
+					}
+				}
+			},
+			write: func(password string) error {
+				return client.DeleteVLAN(mac, password, vlanID)
+			},
+			mismatches: func(config *nsdpclient.VLANConfig) []string {
+				if config.Membership(vlanID) != nil {
+					return []string{fmt.Sprintf("VLAN %d not deleted", vlanID)}
+				}
+				return nil
+			},
+		}, nil
+	})
+}
+
+func runVLANPVID(g *globalOptions, args []string) error {
+	var opts vlanChangeOptions
+	fs := newVLANChangeFlagSet("pvid", g, &opts)
+	id := fs.Uint("id", 0, "VLAN ID (required)")
+	port := fs.Uint("port", 0, "Port (required)")
+	fs.Parse(args)
+
+	vlanID, err := parseVLANID(*id)
+	if err != nil {
+		return err
+	}
+	if *port < 1 || *port > 255 {
+		return fmt.Errorf("a port between 1 and 255 is required (-port)")
+	}
+	portID := uint8(*port)
+
+	return changeVLANConfig(g, opts, func(client *nsdpclient.Client, mac net.HardwareAddr, config *nsdpclient.VLANConfig) (vlanChange, error) {
+		membership := config.Membership(vlanID)
+		if membership == nil || !membership.IsMember(portID) || membership.IsTagged(portID) {
+			return vlanChange{}, fmt.Errorf("port %d must be an untagged member of VLAN %d first", portID, vlanID)
+		}
+		return vlanChange{
+			description: fmt.Sprintf("Port %d: PVID %d -> %d", portID, config.PVID(portID), vlanID),
+			apply: func(config *nsdpclient.VLANConfig) {
+				for i := range config.PVIDs {
+					if config.PVIDs[i].Port == portID {
+						config.PVIDs[i].VLAN = vlanID
+						return
+					}
+				}
+				config.PVIDs = append(config.PVIDs, nsdpclient.PortPVID{Port: portID, VLAN: vlanID})
+			},
+			write: func(password string) error {
+				return client.SetPVID(mac, password, portID, vlanID)
+			},
+			mismatches: func(config *nsdpclient.VLANConfig) []string {
+				if config.PVID(portID) != vlanID {
+					return []string{fmt.Sprintf("port %d: PVID not changed", portID)}
+				}
+				return nil
+			},
+		}, nil
+	})
+}
+
+// vlanChange describes a VLAN configuration change prepared against the
+// current configuration.
+type vlanChange struct {
+	description string
+	apply       func(config *nsdpclient.VLANConfig)          // Applies the change to a copy of the configuration
+	write       func(password string) error                  // Writes the change to the device
+	mismatches  func(config *nsdpclient.VLANConfig) []string // Checks the re-read configuration
+}
+
+// changeVLANConfig runs a VLAN configuration change on the selected device
+// after checking that management access survives it.
+func changeVLANConfig(g *globalOptions, opts vlanChangeOptions, prepare func(client *nsdpclient.Client, mac net.HardwareAddr, config *nsdpclient.VLANConfig) (vlanChange, error)) error {
+	var change vlanChange
+	return changeDevice(g, opts.yes, changeSteps[*nsdpclient.VLANConfig]{
+		title: "NSDP VLAN Configuration Change",
+		what:  "VLAN configuration",
+		read: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) (*nsdpclient.VLANConfig, error) {
+			return client.VLANConfig(device.MAC)
+		},
+		validate: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, config *nsdpclient.VLANConfig) (err error) {
+			if config.Engine != nil && *config.Engine != nsdpclient.VLANEngineAdvanced8021Q {
+				return fmt.Errorf("VLAN engine is %s; switch it to %s first (vlan engine -mode advanced802.1q), which resets the VLAN configuration",
+					formatVLANEngineMode(*config.Engine), formatVLANEngineMode(nsdpclient.VLANEngineAdvanced8021Q))
+			}
+			if change, err = prepare(client, device.MAC, config); err != nil {
+				return err
+			}
+
+			// Check management access against the resulting configuration
+			result := cloneVLANConfig(config)
+			change.apply(result)
+			if err := checkManagementAccess(config, result, opts); err != nil {
+				if !opts.force {
+					return fmt.Errorf("%v; management access could be lost (use -force to apply anyway)", err)
+				}
+				fmt.Printf("WARNING: %v\n", err)
+			}
+			return nil
+		},
+		print: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, _ *nsdpclient.VLANConfig) bool {
+			fmt.Println(change.description)
+			return true
+		},
+		apply: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, password string, _ *nsdpclient.VLANConfig) error {
+			return change.write(password)
+		},
+		mismatches: func(config *nsdpclient.VLANConfig) []string {
+			return change.mismatches(config)
+		},
+	})
+}
+
+// checkManagementAccess checks the resulting configuration for the uplink
+// port. Without a known uplink, changes to the management VLAN's members or
+// to PVIDs from or to it are refused, since their effect on management
+// access cannot be checked.
+func checkManagementAccess(before, after *nsdpclient.VLANConfig, opts vlanChangeOptions) error {
+	mgmtVLAN := uint16(opts.mgmtVLAN)
+	if opts.uplink != 0 {
+		return nsdpclient.CheckManagementAccess(after, mgmtVLAN, uint8(opts.uplink))
+	}
+	if changesVLAN(before, after, mgmtVLAN) {
+		return fmt.Errorf("the change affects management VLAN %d and no uplink port is given (-uplink)", mgmtVLAN)
+	}
+	return nil
+}
+
+// changesVLAN reports whether a VLAN is created, deleted or changes members
+// between two configurations, or a port's PVID changes from or to it.
+func changesVLAN(before, after *nsdpclient.VLANConfig, id uint16) bool {
+	old, updated := before.Membership(id), after.Membership(id)
+	if (old == nil) != (updated == nil) {
+		return true
+	}
+	if old != nil && (!equalPorts(old.Members, updated.Members) || !equalPorts(old.Tagged, updated.Tagged)) {
+		return true
+	}
+	for _, pvid := range after.PVIDs {
+		if previous := before.PVID(pvid.Port); previous != pvid.VLAN && (previous == id || pvid.VLAN == id) {
+			return true
+		}
+	}
+	return false
+}
+
+func runVLANEngine(g *globalOptions, args []string) error {
+	fs := newFlagSet("vlan engine", g)
+	modeName := fs.String("mode", "", "VLAN engine mode, e.g. \"Advanced 802.1Q\" (required)")
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+	fs.Parse(args)
+
+	mode, err := parseVLANEngineMode(*modeName)
+	if err != nil {
+		return err
+	}
+
+	return changeDevice(g, *yes, changeSteps[[]byte]{
+		title: "NSDP VLAN Engine Change",
+		what:  "VLAN engine",
+		read: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo) ([]byte, error) {
+			return queryCustomParameter(client, device.MAC, nsdpclient.ParamVLANEngine, g.verbose), nil
+		},
+		print: func(_ *nsdpclient.Client, _ *nsdpclient.DeviceInfo, engine []byte) bool {
+			current := "unknown"
+			if len(engine) >= 1 {
+				if engine[0] == mode {
+					fmt.Printf("VLAN engine is already %s\n", formatVLANEngineMode(mode))
+					return false
+				}
+				current = formatVLANEngineMode(engine[0])
+			}
+			fmt.Printf("VLAN Engine: %s -> %s\n", current, formatVLANEngineMode(mode))
+			fmt.Println("WARNING: changing the VLAN engine resets the VLAN configuration of the switch")
+			return true
+		},
+		apply: func(client *nsdpclient.Client, device *nsdpclient.DeviceInfo, password string, _ []byte) error {
+			return client.SetVLANEngine(device.MAC, password, mode)
+		},
+		mismatches: func(engine []byte) []string {
+			if len(engine) < 1 || engine[0] != mode {
+				return []string{"VLAN engine not changed"}
+			}
+			return nil
+		},
+	})
+}
+
+func cloneVLANConfig(config *nsdpclient.VLANConfig) *nsdpclient.VLANConfig {
+	clone := *config
+	clone.Memberships = make([]nsdpclient.VLANMembership, len(config.Memberships))
+	for i, m := range config.Memberships {
+		clone.Memberships[i] = nsdpclient.VLANMembership{
+			ID:      m.ID,
+			Members: append([]uint8(nil), m.Members...),
+			Tagged:  append([]uint8(nil), m.Tagged...),
+		}
+	}
+	clone.PVIDs = append([]nsdpclient.PortPVID(nil), config.PVIDs...)
+	return &clone
+}
+
+// sortedPorts returns the ports in ascending order without duplicates.
+func sortedPorts(ports []uint8) []uint8 {
+	var sorted []uint8
+	for _, port := range ports {
+		if !containsPort(sorted, port) {
+			sorted = append(sorted, port)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+func containsPort(ports []uint8, port uint8) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+func equalPorts(a, b []uint8) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+func TestCheckManagementAccess(t *testing.T) {
+	before := &nsdpclient.VLANConfig{
+		Memberships: []nsdpclient.VLANMembership{
+			{ID: 1, Members: []uint8{1, 2, 3, 4, 5, 6, 7, 8}},
+			{ID: 10, Members: []uint8{5, 6, 8}, Tagged: []uint8{8}},
+		},
+		PVIDs: []nsdpclient.PortPVID{{Port: 1, VLAN: 1}, {Port: 5, VLAN: 10}, {Port: 8, VLAN: 1}},
+	}
+	tests := []struct {
+		name   string
+		apply  func(config *nsdpclient.VLANConfig)
+		uplink uint
+		ok     bool
+	}{
+		{"other VLAN members", func(c *nsdpclient.VLANConfig) { c.Memberships[1].Members = []uint8{5, 8} }, 0, true},
+		{"management VLAN members", func(c *nsdpclient.VLANConfig) { c.Memberships[0].Members = []uint8{1, 2} }, 0, false},
+		{"management VLAN deleted", func(c *nsdpclient.VLANConfig) { c.Memberships = c.Memberships[1:] }, 0, false},
+		{"PVID from management VLAN", func(c *nsdpclient.VLANConfig) { c.PVIDs[0].VLAN = 10 }, 0, false},
+		{"PVID to management VLAN", func(c *nsdpclient.VLANConfig) { c.PVIDs[1].VLAN = 1 }, 0, false},
+		{"uplink kept", func(c *nsdpclient.VLANConfig) { c.Memberships[0].Members = []uint8{1, 8} }, 8, true},
+		{"uplink removed", func(c *nsdpclient.VLANConfig) { c.Memberships[0].Members = []uint8{1, 2} }, 8, false},
+	}
+	for _, tt := range tests {
+		after := cloneVLANConfig(before)
+		tt.apply(after)
+		err := checkManagementAccess(before, after, vlanChangeOptions{uplink: tt.uplink, mgmtVLAN: 1})
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: unexpected result %v", tt.name, err)
+		}
+	}
+}
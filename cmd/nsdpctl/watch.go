@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+func runWatch(g *globalOptions, args []string) error {
+	fs := newFlagSet("watch", g)
+	interval := fs.Duration("interval", 5*time.Second, "Time between samples")
+	count := fs.Int("count", 0, "Number of refreshes before exiting (0: until interrupted)")
+	fs.Parse(args)
+
+	if *interval < time.Second {
+		return fmt.Errorf("interval must be at least 1s")
+	}
+	client, err := g.newClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	g.printBanner("NSDP Port Traffic Watch")
+	devices, err := g.discoverDevices(client)
+	if err != nil {
+		return err
+	}
+	var watched []*watchedDevice
+	for _, device := range devices {
+		if device.MAC != nil {
+			watched = append(watched, &watchedDevice{device: device})
+		}
+	}
+	if len(watched) == 0 {
+		return nil
+	}
+
+	// Redraw in place on a terminal, append the views otherwise
+	live := isTerminal(os.Stdout)
+	for _, w := range watched {
+		w.sample(client)
+	}
+	fmt.Printf("Sampling every %v, first rates follow...\n", *interval)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for refresh := 1; *count == 0 || refresh <= *count; refresh++ {
+		<-ticker.C
+		for _, w := range watched {
+			w.sample(client)
+		}
+		if live {
+			fmt.Print("\033[H\033[2J")
+		}
+		printWatch(os.Stdout, watched, *interval)
+	}
+	return nil
+}
+
+// watchedDevice holds the latest statistics sample of a device and the
+// rates since the one before.
+type watchedDevice struct {
+	device  *nsdpclient.DeviceInfo
+	ports   *nsdpclient.PortReport
+	sampled time.Time
+	rates   []nsdpclient.PortRates
+	err     error
+}
+
+// sample reads the port counters. After a failed read the rates are cleared
+// rather than shown as current, and the previous sample is kept, so the next
+// rates span the gap.
+func (w *watchedDevice) sample(client *nsdpclient.Client) {
+	ports, err := client.Ports(w.device.MAC)
+	now := time.Now()
+	w.err = err
+	if err != nil {
+		w.rates = nil
+		return
+	}
+	if w.ports != nil {
+		w.rates = nsdpclient.ComputePortRates(w.ports.Statistics, ports.Statistics, now.Sub(w.sampled))
+	}
+	w.ports, w.sampled = ports, now
+}
+
+func (w *watchedDevice) ratesOf(port uint8) *nsdpclient.PortRates {
+	for i := range w.rates {
+		if w.rates[i].Port == port {
+			return &w.rates[i]
+		}
+	}
+	return nil
+}
+
+// printWatch prints one table of port rates per device.
+func printWatch(out io.Writer, watched []*watchedDevice, interval time.Duration) {
+	fmt.Fprintf(out, "=== NSDP Port Traffic (every %v, %s) ===\n", interval, time.Now().Format("15:04:05"))
+	for _, w := range watched {
+		fmt.Fprintf(out, "\n%s\n", describeDevice(w.device))
+		if w.err != nil {
+			fmt.Fprintf(out, "Error: %v\n", w.err)
+		}
+		if w.ports == nil {
+			continue
+		}
+		if w.err != nil {
+			fmt.Fprintf(out, "Showing the links of %s, no current rates\n", w.sampled.Format("15:04:05"))
+		}
+		fmt.Fprintf(out, "%-5s %-26s %12s %12s %10s %7s\n", "Port", "Link", "RX", "TX", "Packets/s", "Errors")
+		for p := 1; p <= w.ports.Count(); p++ {
+			link := "-"
+			if status := w.ports.StatusOf(uint8(p)); status != nil {
+				link = formatPortStatusByte(status.Status)
+			}
+			rates := w.ratesOf(uint8(p))
+			if rates == nil {
+				fmt.Fprintf(out, "%-5d %-26s %12s %12s %10s %7s\n", p, link, "-", "-", "-", "-")
+				continue
+			}
+			fmt.Fprintf(out, "%-5d %-26s %12s %12s %10.1f %7d", p, link,
+				formatByteRate(rates.RXBytes), formatByteRate(rates.TXBytes), rates.Packets, rates.Errors)
+			if rates.Reset {
+				fmt.Fprint(out, "  (counters reset)")
+			}
+			fmt.Fprintln(out)
+		}
+	}
+}
+
+// isTerminal reports whether f is a character device such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"nsdp/pkg/nsdpclient"
+)
+
+func TestWatchFailedSample(t *testing.T) {
+	// No responder listens, so the sample times out
+	client, err := nsdpclient.New("127.0.0.1:63322", 100*time.Millisecond, false)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ports := &nsdpclient.PortReport{}
+	w := &watchedDevice{
+		device: &nsdpclient.DeviceInfo{MAC: []byte{0x6c, 0xb0, 0xce, 0x1c, 0x83, 0x94}},
+		ports:  ports,
+		rates:  []nsdpclient.PortRates{{Port: 1, RXBytes: 1000}},
+	}
+	w.sample(client)
+	if w.err == nil {
+		t.Fatal("Expected the sample to fail")
+	}
+	if w.rates != nil || w.ports != ports {
+		t.Errorf("Expected the rates cleared and the last sample kept, got %+v", w)
+	}
+}
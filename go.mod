@@ -3,3 +3,5 @@ module nsdp
 go 1.23.12
 
 require github.com/hdecarne-github/go-nsdp v0.3.0
+
+replace github.com/hdecarne-github/go-nsdp => ./third_party/go-nsdp
@@ -0,0 +1,80 @@
+package nsdpclient
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Write-only parameters triggering an action on the device
+const (
+	ParamReboot         = 0x0013 // Reboot the device
+	ParamFactoryReset   = 0x0400 // Restore the factory defaults
+	ParamResetPortStats = 0x1400 // Reset the port statistics
+)
+
+// Interval between discovery attempts while waiting for a rebooting device
+var rebootPollInterval = 2 * time.Second
+
+// Reboot restarts a device. The device may restart before it answers, so a
+// missing response does not mean the reboot failed; only a *WriteError
+// reliably does.
+func (c *Client) Reboot(mac net.HardwareAddr, password string) error {
+	return c.WriteParams(mac, password, ParamValue{Param: ParamReboot, Value: []byte{0x01}})
+}
+
+// FactoryReset restores the factory defaults of a device, including its IP
+// settings and admin password, and restarts it.
+func (c *Client) FactoryReset(mac net.HardwareAddr, password string) error {
+	return c.WriteParams(mac, password, ParamValue{Param: ParamFactoryReset, Value: []byte{0x01}})
+}
+
+// ResetPortStatistics clears the traffic counters of all ports of a device.
+func (c *Client) ResetPortStatistics(mac net.HardwareAddr, password string) error {
+	return c.WriteParams(mac, password, ParamValue{Param: ParamResetPortStats, Value: []byte{0x01}})
+}
+
+// WaitForReboot waits until a rebooting device has stopped answering and
+// answers discovery again, and returns its device information. The device
+// is looked up by MAC, so it is found even if it came back with a new IP
+// address. It gives up after timeout.
+func (c *Client) WaitForReboot(mac net.HardwareAddr, timeout time.Duration) (*DeviceInfo, error) {
+	return waitForReboot(func() (*DeviceInfo, error) {
+		devices, err := c.Discover()
+		if err != nil {
+			return nil, err
+		}
+		for _, device := range devices {
+			if bytes.Equal(device.MAC, mac) {
+				return device, nil
+			}
+		}
+		return nil, fmt.Errorf("device %s not found", mac)
+	}, c.logf, timeout)
+}
+
+// waitForReboot polls probe until it fails at least once and then succeeds.
+func waitForReboot(probe func() (*DeviceInfo, error), logf func(format string, args ...any), timeout time.Duration) (*DeviceInfo, error) {
+	deadline := time.Now().Add(timeout)
+	down := false
+	for {
+		device, err := probe()
+		switch {
+		case err != nil && !down:
+			logf("Device stopped answering, waiting for it to come back...")
+			down = true
+		case err == nil && down:
+			return device, nil
+		case err == nil:
+			logf("Device still answering, waiting for it to restart...")
+		}
+		if time.Now().After(deadline) {
+			if down {
+				return nil, fmt.Errorf("device did not answer again within %v", timeout)
+			}
+			return nil, fmt.Errorf("device kept answering for %v and did not restart", timeout)
+		}
+		time.Sleep(rebootPollInterval)
+	}
+}
@@ -0,0 +1,77 @@
+package nsdpclient
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+func TestResetPortStatistics(t *testing.T) {
+	client := newTestClient(t, testResponse(nsdp.WriteResponse, 0))
+	client.SetPasswordScheme(PasswordXOR)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	if err := client.ResetPortStatistics(mac, "password"); err != nil {
+		t.Errorf("ResetPortStatistics failed: %v", err)
+	}
+}
+
+func TestWaitForReboot(t *testing.T) {
+	interval := rebootPollInterval
+	rebootPollInterval = time.Millisecond
+	t.Cleanup(func() { rebootPollInterval = interval })
+	logf := func(string, ...any) {}
+
+	// Still up, down twice, back again
+	answers := []bool{true, false, false, true}
+	probe := func() (*DeviceInfo, error) {
+		up := answers[0]
+		answers = answers[1:]
+		if !up {
+			return nil, errors.New("timeout")
+		}
+		return &DeviceInfo{Name: "switch1"}, nil
+	}
+	device, err := waitForReboot(probe, logf, time.Second)
+	if err != nil {
+		t.Fatalf("waitForReboot failed: %v", err)
+	}
+	if device.Name != "switch1" || len(answers) != 0 {
+		t.Errorf("Unexpected result: %+v, %d answers left", device, len(answers))
+	}
+
+	// Never goes down
+	alwaysUp := func() (*DeviceInfo, error) { return &DeviceInfo{}, nil }
+	if _, err := waitForReboot(alwaysUp, logf, 10*time.Millisecond); err == nil {
+		t.Error("Expected error for a device that does not restart")
+	}
+
+	// Never comes back
+	alwaysDown := func() (*DeviceInfo, error) { return nil, errors.New("timeout") }
+	if _, err := waitForReboot(alwaysDown, logf, 10*time.Millisecond); err == nil {
+		t.Error("Expected error for a device that does not come back")
+	}
+}
+
+func TestWaitForRebootDiscovery(t *testing.T) {
+	interval := rebootPollInterval
+	rebootPollInterval = time.Millisecond
+	t.Cleanup(func() { rebootPollInterval = interval })
+	other, _ := net.ParseMAC("6c:b0:ce:1c:83:95")
+	// Only another switch answers at first, then the rebooted one is back
+	client := newTestClient(t,
+		testResponse(nsdp.ReadResponse, 0, nsdp.NewDeviceMAC(other)),
+		testDeviceResponse)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	device, err := client.WaitForReboot(mac, 10*time.Second)
+	if err != nil {
+		t.Fatalf("WaitForReboot failed: %v", err)
+	}
+	if device.Name != "switch1" {
+		t.Errorf("Unexpected device: %+v", device)
+	}
+}
@@ -0,0 +1,124 @@
+package nsdpclient
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+// CableStatus is the outcome of a cable test as reported by the device.
+type CableStatus uint32
+
+// Known cable test outcomes
+const (
+	CableOK        CableStatus = 0
+	CableNoCable   CableStatus = 1
+	CableOpen      CableStatus = 2
+	CableShort     CableStatus = 3
+	CableCrosstalk CableStatus = 4
+)
+
+func (s CableStatus) String() string {
+	switch s {
+	case CableOK:
+		return "OK"
+	case CableNoCable:
+		return "No Cable"
+	case CableOpen:
+		return "Open"
+	case CableShort:
+		return "Short"
+	case CableCrosstalk:
+		return "Crosstalk"
+	}
+	return fmt.Sprintf("Unknown (%d)", uint32(s))
+}
+
+// CablePairResult is the test result of a single wire pair.
+type CablePairResult struct {
+	Status        CableStatus
+	FaultDistance uint32 // Distance to the fault in metres (only meaningful if Status is not CableOK)
+}
+
+// CableTestResult holds the cable test results of a port.
+type CableTestResult struct {
+	Port  uint8
+	Pairs []CablePairResult
+}
+
+// Interval between two polls for cable test results
+const cableTestPollInterval = time.Second
+
+// DecodeCableTestResult decodes the cable tester result parameter (0x1c00).
+// The value holds the port number followed by a 32 bit status and a 32 bit
+// fault distance for each tested pair. Most models report a single result
+// covering the whole cable.
+func DecodeCableTestResult(value []byte) (*CableTestResult, error) {
+	if len(value) < 9 || (len(value)-1)%8 != 0 {
+		return nil, fmt.Errorf("unexpected cable test result length: %d", len(value))
+	}
+	result := &CableTestResult{Port: value[0]}
+	for offset := 1; offset < len(value); offset += 8 {
+		result.Pairs = append(result.Pairs, CablePairResult{
+			Status:        CableStatus(binary.BigEndian.Uint32(value[offset : offset+4])),
+			FaultDistance: binary.BigEndian.Uint32(value[offset+4 : offset+8]),
+		})
+	}
+	return result, nil
+}
+
+// StartCableTest triggers a cable test on a port of the device.
+func (c *Client) StartCableTest(mac net.HardwareAddr, port uint8, password string) error {
+	c.logf("Starting cable test on port %d...", port)
+	return c.writeParams(mac, password, []nsdp.TLV{&nsdp.GenericTLV{
+		Type:   ParamCableTest,
+		Length: 2,
+		Value:  []byte{port, 0x01},
+	}})
+}
+
+// CableTestResult reads the cable test result of a port. It returns nil
+// without error if the device has no result for the port yet. The port is
+// the value of the read request's TLV.
+func (c *Client) CableTestResult(mac net.HardwareAddr, port uint8) (*CableTestResult, error) {
+	responseMsg, err := c.sendReceive(mac, []nsdp.TLV{&nsdp.GenericTLV{
+		Type:   ParamCableTesterResult,
+		Length: 1,
+		Value:  []byte{port},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	for _, tlv := range responseMsg.Body {
+		param, value, ok := rawTLV(tlv)
+		if !ok || param != ParamCableTesterResult || len(value) == 0 || value[0] != port {
+			continue
+		}
+		return DecodeCableTestResult(value)
+	}
+	return nil, nil
+}
+
+// TestCable triggers a cable test on a port and polls for its result until it
+// appears or wait has passed.
+func (c *Client) TestCable(mac net.HardwareAddr, port uint8, password string, wait time.Duration) (*CableTestResult, error) {
+	if err := c.StartCableTest(mac, port, password); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		result, err := c.CableTestResult(mac, port)
+		if err != nil || result != nil {
+			return result, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("no cable test result for port %d after %v", port, wait)
+		}
+		c.logf("Cable test on port %d still running...", port)
+		time.Sleep(cableTestPollInterval)
+	}
+}
@@ -0,0 +1,86 @@
+package nsdpclient
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+func TestDecodeCableTestResult(t *testing.T) {
+	result, err := DecodeCableTestResult([]byte{
+		0x03,
+		0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x0c,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	})
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	expected := &CableTestResult{Port: 3, Pairs: []CablePairResult{
+		{Status: CableOpen, FaultDistance: 12},
+		{Status: CableOK},
+	}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+	if got := result.Pairs[0].Status.String(); got != "Open" {
+		t.Errorf("Unexpected status name: %q", got)
+	}
+
+	for _, value := range [][]byte{{0x03}, {0x03, 0x00, 0x00, 0x00, 0x02}} {
+		if _, err := DecodeCableTestResult(value); err == nil {
+			t.Errorf("Expected error for value %x", value)
+		}
+	}
+}
+
+func TestTestCable(t *testing.T) {
+	client := newTestClient(t,
+		testResponse(nsdp.WriteResponse, 0),
+		testResponse(nsdp.ReadResponse, 0, &nsdp.GenericTLV{
+			Type:   ParamCableTesterResult,
+			Length: 9,
+			Value:  []byte{0x05, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x2a},
+		}),
+	)
+	client.SetPasswordScheme(PasswordXOR)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	result, err := client.TestCable(mac, 5, "password", time.Second)
+	if err != nil {
+		t.Fatalf("TestCable failed: %v", err)
+	}
+	expected := &CableTestResult{Port: 5, Pairs: []CablePairResult{{Status: CableShort, FaultDistance: 42}}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestStartCableTestRejected(t *testing.T) {
+	client := newTestClient(t, testResponse(nsdp.WriteResponse, 0x0700))
+	client.SetPasswordScheme(PasswordXOR)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	if err := client.StartCableTest(mac, 1, "wrong"); err == nil {
+		t.Error("Expected error for rejected write request")
+	}
+}
+
+func TestCableTestResultRequest(t *testing.T) {
+	client, requests := newRecordingClient(t, testResponse(nsdp.ReadResponse, 0))
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	if result, err := client.CableTestResult(mac, 5); err != nil || result != nil {
+		t.Fatalf("Expected no result yet, got %+v (%v)", result, err)
+	}
+	operation, tlvs := nextRequest(t, requests)
+	expected := []ParamValue{
+		{Param: uint16(nsdp.TypeDeviceMAC), Value: []byte{}},
+		{Param: ParamCableTesterResult, Value: []byte{5}},
+	}
+	if operation != nsdp.ReadRequest || !reflect.DeepEqual(tlvs, expected) {
+		t.Errorf("Expected read request with %+v, got operation %d with %+v", expected, operation, tlvs)
+	}
+}
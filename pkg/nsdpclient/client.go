@@ -0,0 +1,335 @@
+// Package nsdpclient provides a reusable client for discovering and querying
+// Netgear switches via the Netgear Switch Discovery Protocol (NSDP).
+//
+// It builds on the message and connection handling of the go-nsdp library and
+// adds access to the parameters that library has no dedicated TLV types for.
+package nsdpclient
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+// Client sends NSDP requests over one or more connections, usually one per
+// network interface.
+type Client struct {
+	conns          []*interfaceConn
+	routes         map[string]*interfaceConn // Connection each device answered on, keyed by MAC
+	passwordScheme PasswordScheme
+	verbose        bool
+}
+
+// interfaceConn is a connection bound to a network interface.
+type interfaceConn struct {
+	name string // Interface name (empty if the connection was given by target only)
+	conn *nsdp.Conn
+}
+
+// New creates a client sending its requests to target (usually
+// nsdp.IPv4BroadcastTarget). A non-zero timeout overrides the default
+// receive timeout of the underlying connection.
+func New(target string, timeout time.Duration, verbose bool) (*Client, error) {
+	conn, err := newConn(target, timeout, verbose)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithConn(conn, verbose), nil
+}
+
+// TargetAddress returns the NSDP target address for an IP address, either
+// that of a single switch (e.g. on a routed management network) or a
+// broadcast address.
+func TargetAddress(ip net.IP) string {
+	_, port, _ := net.SplitHostPort(nsdp.IPv4BroadcastTarget)
+	return net.JoinHostPort(ip.String(), port)
+}
+
+// NewWithConn creates a client using an already established connection.
+func NewWithConn(conn *nsdp.Conn, verbose bool) *Client {
+	return &Client{
+		conns:   []*interfaceConn{{conn: conn}},
+		routes:  make(map[string]*interfaceConn),
+		verbose: verbose,
+	}
+}
+
+func newConn(target string, timeout time.Duration, verbose bool) (*nsdp.Conn, error) {
+	conn, err := nsdp.NewConn(target, verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NSDP connection: %w", err)
+	}
+	if timeout > 0 {
+		conn.ReceiveTimeout = timeout
+	}
+	return conn, nil
+}
+
+// Close closes the underlying connections.
+func (c *Client) Close() error {
+	var firstErr error
+	for _, ic := range c.conns {
+		if err := ic.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Conn returns the first underlying connection.
+func (c *Client) Conn() *nsdp.Conn {
+	return c.conns[0].conn
+}
+
+// identificationTLVs returns the TLVs requested by Discover and DeviceInfo.
+func identificationTLVs() []nsdp.TLV {
+	return []nsdp.TLV{
+		// Basic device identification
+		nsdp.EmptyDeviceMAC(),
+		nsdp.EmptyDeviceName(),
+		nsdp.EmptyDeviceModel(),
+		nsdp.EmptyDeviceLocation(),
+
+		// Network configuration
+		nsdp.EmptyDeviceIP(),
+		nsdp.EmptyDeviceNetmask(),
+		nsdp.EmptyRouterIP(),
+		nsdp.EmptyDHCPMode(),
+
+		// Firmware information
+		nsdp.EmptyFWVersionSlot1(),
+		nsdp.EmptyFWVersionSlot2(),
+		nsdp.EmptyNextFWSlot(),
+	}
+}
+
+// Discover broadcasts a read request for the device identification, network
+// and firmware parameters plus any extra TLVs given, and returns the parsed
+// responses ordered by device MAC. With several connections the broadcasts
+// are sent in parallel and a device answering on more than one interface is
+// reported once. Finding no devices is not an error.
+func (c *Client) Discover(extra ...nsdp.TLV) ([]*DeviceInfo, error) {
+	requestMsg := nsdp.NewMessage(nsdp.ReadRequest)
+	for _, tlv := range identificationTLVs() {
+		requestMsg.AppendTLV(tlv)
+	}
+	for _, tlv := range extra {
+		requestMsg.AppendTLV(tlv)
+	}
+
+	type discovery struct {
+		responseMsgs map[string]*nsdp.Message
+		err          error
+	}
+	discoveries := make([]discovery, len(c.conns))
+	var wg sync.WaitGroup
+	for i, ic := range c.conns {
+		wg.Add(1)
+		go func(i int, ic *interfaceConn) {
+			defer wg.Done()
+			if ic.name != "" {
+				c.logf("Sending NSDP discovery request on %s...", ic.name)
+			} else {
+				c.logf("Sending NSDP discovery request...")
+			}
+			discoveries[i].responseMsgs, discoveries[i].err = ic.conn.SendReceiveMessage(requestMsg)
+		}(i, ic)
+	}
+	wg.Wait()
+
+	var devices []*DeviceInfo
+	seen := make(map[string]bool)
+	for i, d := range discoveries {
+		ic := c.conns[i]
+		if d.err != nil {
+			if len(c.conns) == 1 {
+				return nil, fmt.Errorf("failed to send/receive NSDP message: %w", d.err)
+			}
+			c.logf("Discovery on %s failed: %v", ic.name, d.err)
+			continue
+		}
+		for key, responseMsg := range d.responseMsgs {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			c.routes[key] = ic
+			device := ParseDeviceInfo(responseMsg)
+			device.Interface = ic.name
+			devices = append(devices, device)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return bytes.Compare(devices[i].MAC, devices[j].MAC) < 0
+	})
+	return devices, nil
+}
+
+// DeviceInfo reads the identification, network and firmware parameters plus
+// any extra TLVs given of a single device. Unlike Discover, only the
+// addressed device answers.
+func (c *Client) DeviceInfo(mac net.HardwareAddr, extra ...nsdp.TLV) (*DeviceInfo, error) {
+	responseMsg, err := c.sendReceive(mac, append(identificationTLVs(), extra...))
+	if err != nil {
+		return nil, err
+	}
+	device := ParseDeviceInfo(responseMsg)
+	if device.MAC == nil {
+		device.MAC = mac
+	}
+	device.Interface = c.routes[mac.String()].name
+	return device, nil
+}
+
+// Params holds the raw values a device returned for a read request, keyed by
+// parameter code. Parameters reported once per port or per VLAN carry one
+// record per entry, in the order the device sent them.
+type Params map[uint16][][]byte
+
+// Get returns the first record of the given parameter, or nil if the device
+// did not report it.
+func (p Params) Get(param uint16) []byte {
+	if records := p[param]; len(records) > 0 {
+		return records[0]
+	}
+	return nil
+}
+
+// Records returns all records of the given parameter.
+func (p Params) Records(param uint16) [][]byte {
+	return p[param]
+}
+
+// ReadParams reads the given parameters from a single device and returns
+// their raw values.
+func (c *Client) ReadParams(mac net.HardwareAddr, params ...uint16) (Params, error) {
+	tlvs := make([]nsdp.TLV, 0, len(params))
+	for _, param := range params {
+		tlvs = append(tlvs, &nsdp.GenericTLV{
+			Type:   param,
+			Length: 0, // Empty for read request
+			Value:  nil,
+		})
+	}
+	responseMsg, err := c.sendReceive(mac, tlvs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(Params)
+	for _, tlv := range responseMsg.Body {
+		param, value, ok := rawTLV(tlv)
+		if !ok {
+			continue
+		}
+		result[param] = append(result[param], value)
+	}
+	for _, param := range params {
+		if records, ok := result[param]; ok {
+			c.logf("Found %s: %d record(s)", ParamDescription(param), len(records))
+		} else {
+			c.logf("Parameter 0x%04x: No response", param)
+		}
+	}
+	return result, nil
+}
+
+// ReadParam reads a single parameter from a device. It returns nil without
+// error if the device answered but did not report the parameter.
+func (c *Client) ReadParam(mac net.HardwareAddr, param uint16) ([]byte, error) {
+	result, err := c.ReadParams(mac, param)
+	if err != nil {
+		return nil, err
+	}
+	return result.Get(param), nil
+}
+
+// PortStatistics reads the traffic counters of all ports of a device,
+// ordered by port.
+func (c *Client) PortStatistics(mac net.HardwareAddr) ([]*nsdp.PortStatistic, error) {
+	responseMsg, err := c.sendReceive(mac, []nsdp.TLV{nsdp.EmptyPortStatistic()})
+	if err != nil {
+		return nil, err
+	}
+	return c.newPortReport(responseMsg.Body).Statistics, nil
+}
+
+// sendReceive sends a read request for the given TLVs to a single device and
+// returns its response.
+func (c *Client) sendReceive(mac net.HardwareAddr, tlvs []nsdp.TLV) (*nsdp.Message, error) {
+	return c.sendRequest(nsdp.ReadRequest, mac, tlvs)
+}
+
+// sendRequest sends a request for the given TLVs to a single device and
+// returns its response. The request goes out on the connection the device
+// last answered on, or on each connection in turn if it is unknown.
+func (c *Client) sendRequest(operation nsdp.OperationCode, mac net.HardwareAddr, tlvs []nsdp.TLV) (*nsdp.Message, error) {
+	requestMsg := nsdp.NewMessage(operation)
+	requestMsg.Header.DeviceAddress = mac
+	// Write requests must start with the password TLV; the header address
+	// alone targets the device.
+	if operation == nsdp.ReadRequest {
+		requestMsg.AppendTLV(nsdp.NewDeviceMAC(mac))
+	}
+	for _, tlv := range tlvs {
+		requestMsg.AppendTLV(tlv)
+	}
+
+	conns := c.conns
+	if route, ok := c.routes[mac.String()]; ok {
+		conns = []*interfaceConn{route}
+	}
+	var err error
+	for _, ic := range conns {
+		var responseMsg *nsdp.Message
+		if responseMsg, err = c.sendRequestOn(ic, requestMsg, mac); err == nil {
+			c.routes[mac.String()] = ic
+			return responseMsg, nil
+		}
+	}
+	return nil, err
+}
+
+func (c *Client) sendRequestOn(ic *interfaceConn, requestMsg *nsdp.Message, mac net.HardwareAddr) (*nsdp.Message, error) {
+	responseMsgs, err := ic.conn.SendReceiveMessage(requestMsg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query device %s: %w", mac, err)
+	}
+	if responseMsg, ok := responseMsgs[mac.String()]; ok {
+		return responseMsg, nil
+	}
+	// Some devices answer with an empty header address; accept a lone reply
+	if len(responseMsgs) == 1 {
+		for _, responseMsg := range responseMsgs {
+			return responseMsg, nil
+		}
+	}
+	return nil, fmt.Errorf("no response from device %s", mac)
+}
+
+// rawTLV returns the parameter code and raw value of a TLV, regardless of
+// whether the library decoded it into a dedicated type.
+func rawTLV(tlv nsdp.TLV) (uint16, []byte, bool) {
+	switch v := tlv.(type) {
+	case *nsdp.GenericTLV:
+		return v.Type, v.Value, true
+	case interface {
+		Type() nsdp.Type
+		Value() []byte
+	}:
+		return uint16(v.Type()), v.Value(), true
+	}
+	return 0, nil, false
+}
+
+func (c *Client) logf(format string, args ...any) {
+	if c.verbose {
+		log.Printf(format, args...)
+	}
+}
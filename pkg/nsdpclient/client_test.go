@@ -0,0 +1,218 @@
+package nsdpclient
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+// Response of a GS108Ev3 to a discovery request (taken from go-nsdp's tests)
+const testDeviceResponse = "0102000000000000bcd07432b8dc6cb0ce1c8394000099d14e534450000000000001000847533130384576330003000773776974636831000400066cb0ce1c839400050000000600040a01000300070004ffff0000000800040a010001000b000100000d0007322e30362e3137000e0000000f0001010c0000030105000c0000030200000c0000030304000c0000030400000c0000030504000c0000030600000c0000030700000c0000030800001000003101000000011b86e2c2000000000d159e3800000000000000000000000000000000000000000000000000000000000000001000003102000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000100000310300000000039bd6ce000000000874984f000000000000000000000000000000000000000000000000000000000000000010000031040000000000133f340000000000cf6d03000000000000000000000000000000000000000000000000000000000000000010000031050000000009668768000000010afa8d1d0000000000000000000000000000000000000000000000000000000000000000100000310600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010000031070000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000001000003108000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000ffff0000"
+
+// Use a port pair distinct from the cmd tests, as packages are tested in parallel
+const testTarget = "127.0.0.1:63422"
+
+func newTestClient(t *testing.T, responses ...string) *Client {
+	t.Helper()
+	responder, err := nsdp.NewTestResponder(testTarget)
+	if err != nil {
+		t.Fatalf("Failed to create test responder: %v", err)
+	}
+	for _, response := range responses {
+		responder.AddResponses(response)
+	}
+	if err := responder.Start(); err != nil {
+		t.Fatalf("Failed to start test responder: %v", err)
+	}
+	t.Cleanup(func() { responder.Stop() })
+
+	client, err := New(testTarget, 0, false)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// testResponse returns a hex encoded response message with the given TLVs.
+func testResponse(operation nsdp.OperationCode, result nsdp.OperationResult, tlvs ...nsdp.TLV) string {
+	msg := nsdp.NewMessage(operation)
+	msg.Header.Result = result
+	for _, tlv := range tlvs {
+		msg.AppendTLV(tlv)
+	}
+	return hex.EncodeToString(msg.Marshal())
+}
+
+// newRecordingClient works like newTestClient, but also returns the raw
+// requests the client sent, so tests can check what goes on the wire. The
+// responder answers each request with the next response, taking over the
+// request's sequence number like a device would.
+func newRecordingClient(t *testing.T, responses ...string) (*Client, <-chan []byte) {
+	t.Helper()
+	addr, _ := net.ResolveUDPAddr("udp", testTarget)
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		t.Fatalf("Failed to create recording responder: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	requests := make(chan []byte, len(responses))
+	go func() {
+		buffer := make([]byte, 8192)
+		for _, response := range responses {
+			n, from, err := conn.ReadFromUDP(buffer)
+			if err != nil {
+				return
+			}
+			request := append([]byte(nil), buffer[:n]...)
+			requests <- request
+			requestMsg, err := nsdp.UnmarshalMessage(request)
+			if err != nil {
+				t.Errorf("Invalid request %x: %v", request, err)
+				return
+			}
+			responseBytes, _ := hex.DecodeString(response)
+			responseMsg, _ := nsdp.UnmarshalMessage(responseBytes)
+			responseMsg.Header.HostAddress = requestMsg.Header.HostAddress
+			responseMsg.Header.Sequence = requestMsg.Header.Sequence
+			conn.WriteToUDP(responseMsg.Marshal(), from)
+		}
+	}()
+
+	client, err := New(testTarget, 0, false)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client, requests
+}
+
+// nextRequest returns the next recorded request, decoded into its header
+// operation and TLVs with their raw values.
+func nextRequest(t *testing.T, requests <-chan []byte) (nsdp.OperationCode, []ParamValue) {
+	t.Helper()
+	var request []byte
+	select {
+	case request = <-requests:
+	case <-time.After(time.Second):
+		t.Fatal("No request recorded")
+	}
+	const headerLen = 32
+	if len(request) < headerLen+4 {
+		t.Fatalf("Request too short: %x", request)
+	}
+	var tlvs []ParamValue
+	for offset := headerLen; offset+4 <= len(request); {
+		param := binary.BigEndian.Uint16(request[offset:])
+		length := int(binary.BigEndian.Uint16(request[offset+2:]))
+		if param == 0xffff {
+			break
+		}
+		offset += 4
+		if offset+length > len(request) {
+			t.Fatalf("TLV %04x exceeds request: %x", param, request)
+		}
+		tlvs = append(tlvs, ParamValue{Param: param, Value: request[offset : offset+length]})
+		offset += length
+	}
+	return nsdp.OperationCode(request[1]), tlvs
+}
+
+func TestDiscover(t *testing.T) {
+	client := newTestClient(t, testDeviceResponse)
+
+	devices, err := client.Discover()
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(devices) != 1 {
+		t.Fatalf("Expected 1 device, got %d", len(devices))
+	}
+
+	device := devices[0]
+	if device.MAC.String() != "6c:b0:ce:1c:83:94" {
+		t.Errorf("Unexpected MAC: %s", device.MAC)
+	}
+	if device.Model != "GS108Ev3" {
+		t.Errorf("Unexpected model: %q", device.Model)
+	}
+	if device.Name != "switch1" {
+		t.Errorf("Unexpected name: %q", device.Name)
+	}
+	if !device.IP.Equal(net.IPv4(10, 1, 0, 3)) {
+		t.Errorf("Unexpected IP: %s", device.IP)
+	}
+	if device.DHCPMode == nil || *device.DHCPMode != 0 {
+		t.Errorf("Unexpected DHCP mode: %v", device.DHCPMode)
+	}
+	if device.FWVersionSlot1 != "2.06.17" || device.NextFWSlot != 1 {
+		t.Errorf("Unexpected firmware info: %q, slot %d", device.FWVersionSlot1, device.NextFWSlot)
+	}
+	if len(device.Ports) != 8 {
+		t.Errorf("Expected 8 port status records, got %d", len(device.Ports))
+	}
+}
+
+func TestDeviceInfo(t *testing.T) {
+	client := newTestClient(t, testDeviceResponse)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	device, err := client.DeviceInfo(mac, nsdp.EmptyPortStatus())
+	if err != nil {
+		t.Fatalf("DeviceInfo failed: %v", err)
+	}
+	if device.MAC.String() != mac.String() || device.Name != "switch1" {
+		t.Errorf("Unexpected device: %s %q", device.MAC, device.Name)
+	}
+	if len(device.Ports) != 8 {
+		t.Errorf("Expected 8 port status records, got %d", len(device.Ports))
+	}
+}
+
+func TestReadParams(t *testing.T) {
+	client := newTestClient(t, testDeviceResponse)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	params, err := client.ReadParams(mac, ParamPortStatus, ParamPortStatistics, ParamLoopDetection)
+	if err != nil {
+		t.Fatalf("ReadParams failed: %v", err)
+	}
+	if records := params.Records(ParamPortStatus); len(records) != 8 {
+		t.Errorf("Expected 8 port status records, got %d", len(records))
+	}
+	if first := params.Get(ParamPortStatistics); len(first) != 49 || first[0] != 1 {
+		t.Errorf("Unexpected first port statistics record: %x", first)
+	}
+	if params.Get(ParamLoopDetection) != nil {
+		t.Error("Expected no value for an unreported parameter")
+	}
+}
+
+func TestParamDescription(t *testing.T) {
+	if got := ParamDescription(ParamVLANPVID); got != "802.1Q PVID" {
+		t.Errorf("Unexpected description: %q", got)
+	}
+	if got := ParamDescription(0x1234); got != "Parameter 0x1234" {
+		t.Errorf("Unexpected fallback description: %q", got)
+	}
+	if got := ParamDescription(ParamIGMPRouterPorts); got != "IGMP Router Ports / Unknown IGMP Parameter (0x8000)" {
+		t.Errorf("Unexpected description of an ambiguous code: %q", got)
+	}
+}
+
+func TestDescribeParam(t *testing.T) {
+	if got := DescribeParam(ParamIGMPRouterPorts, "igmp", "GS108Ev3"); got != "IGMP Router Ports" {
+		t.Errorf("Unexpected IGMP description: %q", got)
+	}
+	if got := DescribeParam(ParamIGMPRouterPorts, "scan", ""); got != "Unknown IGMP Parameter (0x8000)" {
+		t.Errorf("Unexpected scan description: %q", got)
+	}
+	if got := len(Interpretations(ParamIGMPRouterPorts, "", "")); got != 2 {
+		t.Errorf("Expected 2 interpretations, got %d", got)
+	}
+}
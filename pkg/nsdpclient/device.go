@@ -0,0 +1,80 @@
+package nsdpclient
+
+import (
+	"net"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+// DeviceInfo holds the identification, network and firmware details a
+// device reports in response to a discovery request.
+type DeviceInfo struct {
+	MAC      net.HardwareAddr
+	Name     string
+	Model    string
+	Location string
+
+	IP       net.IP
+	Netmask  net.IP
+	Gateway  net.IP
+	DHCPMode *uint8 // nil if the device did not report its DHCP mode
+
+	FWVersionSlot1 string
+	FWVersionSlot2 string
+	NextFWSlot     uint8 // 0 if not reported
+
+	Ports []*nsdp.PortStatus
+	VLANs []*nsdp.VLANInfo
+
+	// TLVs the parser does not know how to interpret
+	Unknown []nsdp.TLV
+
+	// Network interface the device answered on (empty if the client is not
+	// bound to interfaces)
+	Interface string
+}
+
+// ParseDeviceInfo collects the known TLVs of a response message into a DeviceInfo.
+func ParseDeviceInfo(msg *nsdp.Message) *DeviceInfo {
+	info := &DeviceInfo{}
+	for _, tlv := range msg.Body {
+		switch v := tlv.(type) {
+		case *nsdp.DeviceMAC:
+			if v.MAC != nil {
+				info.MAC = v.MAC
+			}
+		case *nsdp.DeviceName:
+			info.Name = v.Name
+		case *nsdp.DeviceModel:
+			info.Model = v.Model
+		case *nsdp.DeviceLocation:
+			info.Location = v.Location
+		case *nsdp.DeviceIP:
+			info.IP = v.IP
+		case *nsdp.DeviceNetmask:
+			info.Netmask = v.Netmask
+		case *nsdp.RouterIP:
+			info.Gateway = v.IP
+		case *nsdp.DHCPMode:
+			mode := v.Mode
+			info.DHCPMode = &mode
+		case *nsdp.FWVersionSlot1:
+			info.FWVersionSlot1 = v.Version
+		case *nsdp.FWVersionSlot2:
+			info.FWVersionSlot2 = v.Version
+		case *nsdp.NextFWSlot:
+			info.NextFWSlot = v.Slot
+		case *nsdp.PortStatus:
+			info.Ports = append(info.Ports, v)
+		case *nsdp.VLANInfo:
+			info.VLANs = append(info.VLANs, v)
+		default:
+			info.Unknown = append(info.Unknown, tlv)
+		}
+	}
+	// Fall back to the header address for devices omitting the MAC TLV
+	if info.MAC == nil && msg.Header != nil {
+		info.MAC = msg.Header.DeviceAddress
+	}
+	return info
+}
@@ -0,0 +1,212 @@
+package nsdpclient
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+// ParamFirmwareUpgrade makes the device fetch a firmware image from a TFTP
+// server and write it to its inactive slot (write only).
+const ParamFirmwareUpgrade = 0x0010
+
+// FirmwareInfo is the firmware image layout of a device. Dual image devices
+// hold a firmware in each of two slots and boot from the one selected with
+// the 0x000f parameter.
+type FirmwareInfo struct {
+	Slots    [2]string // Firmware version in slot 1 and 2 (empty if unused)
+	NextSlot uint8     // Slot the device boots from next (0: not reported)
+}
+
+// FirmwareOf returns the firmware layout reported in a device's
+// identification.
+func FirmwareOf(info *DeviceInfo) FirmwareInfo {
+	return FirmwareInfo{
+		Slots:    [2]string{info.FWVersionSlot1, info.FWVersionSlot2},
+		NextSlot: info.NextFWSlot,
+	}
+}
+
+// DualImage reports whether the device has two firmware slots.
+func (f FirmwareInfo) DualImage() bool {
+	return f.Slots[1] != "" || f.NextSlot != 0
+}
+
+// Version returns the firmware version in a slot (1 or 2).
+func (f FirmwareInfo) Version(slot uint8) string {
+	if slot < 1 || slot > 2 {
+		return ""
+	}
+	return f.Slots[slot-1]
+}
+
+// BootVersion returns the firmware version the device boots next: the one in
+// the next boot slot, or the only one of a single image device. NSDP has no
+// parameter for the running slot, which differs from the next boot slot once
+// that is changed, so the running version of a dual image device is unknown.
+func (f FirmwareInfo) BootVersion() string {
+	if !f.DualImage() {
+		return f.Version(1)
+	}
+	return f.Version(f.NextSlot)
+}
+
+// SetNextFirmwareSlot selects the firmware slot a dual image device boots
+// from next. The change takes effect with the next reboot.
+func (c *Client) SetNextFirmwareSlot(mac net.HardwareAddr, password string, slot uint8) error {
+	if slot != 1 && slot != 2 {
+		return fmt.Errorf("invalid firmware slot %d", slot)
+	}
+	return c.writeParams(mac, password, []nsdp.TLV{nsdp.NewNextFWSlot(slot)})
+}
+
+// FirmwareGroup is a set of devices of the same model booting the same
+// firmware version.
+type FirmwareGroup struct {
+	Model   string
+	Version string
+	Devices []*DeviceInfo
+}
+
+// GroupByFirmware groups devices by model and the firmware version they boot
+// next, ordered by model and version.
+func GroupByFirmware(devices []*DeviceInfo) []FirmwareGroup {
+	var groups []FirmwareGroup
+	index := make(map[[2]string]int)
+	for _, device := range devices {
+		key := [2]string{device.Model, FirmwareOf(device).BootVersion()}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, FirmwareGroup{Model: key[0], Version: key[1]})
+		}
+		groups[i].Devices = append(groups[i].Devices, device)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Model != groups[j].Model {
+			return groups[i].Model < groups[j].Model
+		}
+		return groups[i].Version < groups[j].Version
+	})
+	return groups
+}
+
+// Length of the header at the start of a firmware image
+const firmwareHeaderLen = 256
+
+var firmwareVersionPattern = regexp.MustCompile(`^[vV]?(\d+\.\d+\.\d+(\.\d+)?)$`)
+
+// FirmwareImage is a firmware image file along with the model and version
+// named in its header.
+type FirmwareImage struct {
+	Model   string
+	Version string // Without a "V" prefix, empty if the header names none
+	Data    []byte
+}
+
+// ParseFirmwareImage reads the header of a firmware image. The header holds
+// NUL separated ASCII strings, the first naming the model, followed by the
+// firmware version among other build information.
+func ParseFirmwareImage(data []byte) (*FirmwareImage, error) {
+	if len(data) <= firmwareHeaderLen {
+		return nil, fmt.Errorf("image too short: %d bytes", len(data))
+	}
+	image := &FirmwareImage{Data: data}
+	for _, field := range strings.Split(string(data[:firmwareHeaderLen]), "\x00") {
+		field = strings.TrimSpace(field)
+		if field == "" || !isPrintable(field) {
+			continue
+		}
+		if image.Model == "" {
+			image.Model = field
+			continue
+		}
+		if m := firmwareVersionPattern.FindStringSubmatch(field); m != nil && image.Version == "" {
+			image.Version = m[1]
+		}
+	}
+	if image.Model == "" {
+		return nil, fmt.Errorf("no model found in the image header")
+	}
+	return image, nil
+}
+
+// MatchesModel reports whether the image is built for a device model.
+// Hardware revisions must match, so a GS108Ev2 image does not fit a
+// GS108Ev3.
+func (i *FirmwareImage) MatchesModel(model string) bool {
+	return model != "" && strings.EqualFold(strings.TrimSpace(model), i.Model)
+}
+
+// Slot returns the slot of a device holding the image version, or 0 if none
+// does.
+func (f FirmwareInfo) Slot(version string) uint8 {
+	for slot := uint8(1); slot <= 2; slot++ {
+		if sameVersion(f.Version(slot), version) {
+			return slot
+		}
+	}
+	return 0
+}
+
+// UpgradedSlot returns the slot an upgrade to the image version went to,
+// given the layout before the upgrade: a slot that did not hold the version
+// before, or else the next boot slot if it holds the version. It returns 0
+// if the upgrade cannot be confirmed, e.g. because the version only shows up
+// in the slot that already held it.
+func (f FirmwareInfo) UpgradedSlot(before FirmwareInfo, version string) uint8 {
+	for slot := uint8(1); slot <= 2; slot++ {
+		if sameVersion(f.Version(slot), version) && !sameVersion(before.Version(slot), version) {
+			return slot
+		}
+	}
+	bootSlot := f.NextSlot
+	if !f.DualImage() {
+		bootSlot = 1
+	}
+	if sameVersion(f.Version(bootSlot), version) {
+		return bootSlot
+	}
+	return 0
+}
+
+// sameVersion reports whether a slot version is the image version, ignoring
+// a "v" prefix.
+func sameVersion(slotVersion, version string) bool {
+	version = strings.TrimLeft(version, "vV")
+	return version != "" && strings.TrimLeft(slotVersion, "vV") == version
+}
+
+// EncodeFirmwareUpgrade encodes the 0x0010 value: the IPv4 address of the
+// TFTP server followed by the name of the image file.
+func EncodeFirmwareUpgrade(server net.IP, file string) ([]byte, error) {
+	ip := server.To4()
+	if ip == nil {
+		return nil, fmt.Errorf("TFTP server address %s is not IPv4", server)
+	}
+	return append(append([]byte{}, ip...), file...), nil
+}
+
+// StartFirmwareUpgrade makes a device fetch a firmware image from a TFTP
+// server. The device writes it to its inactive slot and restarts.
+func (c *Client) StartFirmwareUpgrade(mac net.HardwareAddr, password string, server net.IP, file string) error {
+	value, err := EncodeFirmwareUpgrade(server, file)
+	if err != nil {
+		return err
+	}
+	return c.WriteParams(mac, password, ParamValue{Param: ParamFirmwareUpgrade, Value: value})
+}
+
+func isPrintable(s string) bool {
+	for _, r := range s {
+		if r < 0x20 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
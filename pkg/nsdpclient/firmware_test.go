@@ -0,0 +1,145 @@
+package nsdpclient
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+func TestFirmwareInfo(t *testing.T) {
+	single := FirmwareOf(&DeviceInfo{FWVersionSlot1: "1.00.10"})
+	if single.DualImage() || single.BootVersion() != "1.00.10" {
+		t.Errorf("Unexpected single image layout: %+v", single)
+	}
+
+	dual := FirmwareOf(&DeviceInfo{FWVersionSlot1: "2.06.17", FWVersionSlot2: "2.06.24", NextFWSlot: 2})
+	if !dual.DualImage() || dual.BootVersion() != "2.06.24" {
+		t.Errorf("Unexpected dual image layout: %+v", dual)
+	}
+	if dual.Version(3) != "" {
+		t.Error("Expected no version for slot 3")
+	}
+
+	unreported := FirmwareOf(&DeviceInfo{FWVersionSlot1: "2.06.17", FWVersionSlot2: "2.06.24"})
+	if unreported.BootVersion() != "" {
+		t.Errorf("Expected no boot version without a next boot slot, got %q", unreported.BootVersion())
+	}
+}
+
+func TestGroupByFirmware(t *testing.T) {
+	devices := []*DeviceInfo{
+		{Name: "a", Model: "GS308E", FWVersionSlot1: "1.00.10"},
+		{Name: "b", Model: "GS108Ev3", FWVersionSlot1: "2.06.24"},
+		{Name: "c", Model: "GS108Ev3", FWVersionSlot1: "2.06.17"},
+		{Name: "d", Model: "GS108Ev3", FWVersionSlot1: "2.06.24"},
+	}
+	groups := GroupByFirmware(devices)
+	if len(groups) != 3 {
+		t.Fatalf("Expected 3 groups, got %d", len(groups))
+	}
+	expected := []struct {
+		model, version string
+		count          int
+	}{
+		{"GS108Ev3", "2.06.17", 1},
+		{"GS108Ev3", "2.06.24", 2},
+		{"GS308E", "1.00.10", 1},
+	}
+	for i, e := range expected {
+		if groups[i].Model != e.model || groups[i].Version != e.version || len(groups[i].Devices) != e.count {
+			t.Errorf("Group %d: expected %s %s (%d), got %s %s (%d)", i, e.model, e.version, e.count,
+				groups[i].Model, groups[i].Version, len(groups[i].Devices))
+		}
+	}
+}
+
+func TestSetNextFirmwareSlot(t *testing.T) {
+	client := newTestClient(t, testResponse(nsdp.WriteResponse, 0))
+	client.SetPasswordScheme(PasswordXOR)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	if err := client.SetNextFirmwareSlot(mac, "password", 3); err == nil {
+		t.Error("Expected error for invalid slot")
+	}
+	if err := client.SetNextFirmwareSlot(mac, "password", 2); err != nil {
+		t.Errorf("SetNextFirmwareSlot failed: %v", err)
+	}
+}
+
+func testFirmwareImage(header ...string) []byte {
+	data := make([]byte, firmwareHeaderLen+64)
+	copy(data, strings.Join(header, "\x00"))
+	return data
+}
+
+func TestParseFirmwareImage(t *testing.T) {
+	image, err := ParseFirmwareImage(testFirmwareImage("GS108Ev3", "Build 2023-05-04", "V2.06.24"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if image.Model != "GS108Ev3" || image.Version != "2.06.24" {
+		t.Errorf("Unexpected header: %s %s", image.Model, image.Version)
+	}
+	if !image.MatchesModel("gs108ev3") || image.MatchesModel("GS108Ev2") || image.MatchesModel("") {
+		t.Error("Unexpected model match")
+	}
+
+	if _, err := ParseFirmwareImage(testFirmwareImage()); err == nil {
+		t.Error("Expected error for a header without model")
+	}
+	if _, err := ParseFirmwareImage([]byte("GS108Ev3")); err == nil {
+		t.Error("Expected error for a short image")
+	}
+}
+
+func TestFirmwareSlot(t *testing.T) {
+	firmware := FirmwareInfo{Slots: [2]string{"V2.06.17", "2.06.24"}, NextSlot: 1}
+	if slot := firmware.Slot("2.06.17"); slot != 1 {
+		t.Errorf("Expected slot 1, got %d", slot)
+	}
+	if slot := firmware.Slot("V2.06.24"); slot != 2 {
+		t.Errorf("Expected slot 2, got %d", slot)
+	}
+	if slot := firmware.Slot(""); slot != 0 {
+		t.Errorf("Expected no slot, got %d", slot)
+	}
+}
+
+func TestEncodeFirmwareUpgrade(t *testing.T) {
+	value, err := EncodeFirmwareUpgrade(net.ParseIP("192.168.0.10"), "fw.bin")
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if string(value) != "\xc0\xa8\x00\x0afw.bin" {
+		t.Errorf("Unexpected value: %x", value)
+	}
+	if _, err := EncodeFirmwareUpgrade(net.ParseIP("fe80::1"), "fw.bin"); err == nil {
+		t.Error("Expected error for IPv6 server")
+	}
+}
+
+func TestUpgradedSlot(t *testing.T) {
+	before := FirmwareInfo{Slots: [2]string{"2.06.17", "2.06.24"}, NextSlot: 1}
+	for _, tt := range []struct {
+		name     string
+		after    FirmwareInfo
+		version  string
+		expected uint8
+	}{
+		{"written to the other slot", FirmwareInfo{Slots: [2]string{"2.06.17", "2.06.30"}, NextSlot: 2}, "2.06.30", 2},
+		{"only in the old slot", FirmwareInfo{Slots: [2]string{"2.06.17", "2.06.24"}, NextSlot: 1}, "v2.06.24", 0},
+		{"reinstalled to the next boot slot", FirmwareInfo{Slots: [2]string{"2.06.17", "2.06.24"}, NextSlot: 2}, "2.06.24", 2},
+		{"not written", FirmwareInfo{Slots: [2]string{"2.06.17", "2.06.24"}, NextSlot: 1}, "2.06.30", 0},
+	} {
+		if slot := tt.after.UpgradedSlot(before, tt.version); slot != tt.expected {
+			t.Errorf("%s: expected slot %d, got %d", tt.name, tt.expected, slot)
+		}
+	}
+
+	single := FirmwareInfo{Slots: [2]string{"1.00.10"}}
+	if slot := single.UpgradedSlot(single, "1.00.10"); slot != 1 {
+		t.Errorf("Expected slot 1 for a reinstalled single image, got %d", slot)
+	}
+}
@@ -0,0 +1,185 @@
+package nsdpclient
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// DecodeIGMPRouterPorts decodes the IGMP snooping static router ports
+// parameter (0x8000), a port bitmap whose width depends on the port count.
+func DecodeIGMPRouterPorts(value []byte) ([]uint8, error) {
+	if len(value) == 0 {
+		return nil, fmt.Errorf("empty IGMP router ports value")
+	}
+	return decodePortBitmap(value), nil
+}
+
+// IGMPConfig is the IGMP snooping configuration of a device. Flags are nil
+// if the device does not report them.
+type IGMPConfig struct {
+	Snooping              *bool
+	SnoopingVLAN          uint16
+	BlockUnknownMulticast *bool
+	ValidateIGMPv3        *bool
+	RouterPorts           []uint8
+	BitmapWidth           int // Width of the router port bitmap in bytes (0: not reported)
+}
+
+// IGMPConfig reads the IGMP snooping configuration of a device in one
+// request.
+func (c *Client) IGMPConfig(mac net.HardwareAddr) (*IGMPConfig, error) {
+	params, err := c.ReadParams(mac, ParamIGMPSnooping, ParamBlockUnknownMcast, ParamValidateIGMPv3, ParamIGMPRouterPorts)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &IGMPConfig{}
+	if snooping := params.Get(ParamIGMPSnooping); len(snooping) >= 4 {
+		enabled := snooping[1] != 0x00
+		config.Snooping = &enabled
+		config.SnoopingVLAN = binary.BigEndian.Uint16(snooping[2:4])
+	}
+	if block := params.Get(ParamBlockUnknownMcast); len(block) >= 1 {
+		enabled := block[0] != 0x00
+		config.BlockUnknownMulticast = &enabled
+	}
+	if validate := params.Get(ParamValidateIGMPv3); len(validate) >= 1 {
+		enabled := validate[0] != 0x00
+		config.ValidateIGMPv3 = &enabled
+	}
+	if routerPorts := params.Get(ParamIGMPRouterPorts); routerPorts != nil {
+		if config.RouterPorts, err = DecodeIGMPRouterPorts(routerPorts); err != nil {
+			return nil, err
+		}
+		config.BitmapWidth = len(routerPorts)
+	}
+	return config, nil
+}
+
+// EncodeIGMPSnooping encodes the IGMP snooping state and the VLAN it runs on
+// as a 0x6800 value.
+func EncodeIGMPSnooping(enabled bool, vlan uint16) []byte {
+	value := []byte{0x00, boolByte(enabled), 0, 0}
+	binary.BigEndian.PutUint16(value[2:], vlan)
+	return value
+}
+
+// IGMPSettings holds IGMP snooping settings to change. Nil fields are left
+// unchanged.
+type IGMPSettings struct {
+	Snooping              *bool
+	SnoopingVLAN          *uint16
+	BlockUnknownMulticast *bool
+	ValidateIGMPv3        *bool
+	RouterPorts           *[]uint8
+}
+
+// IsEmpty reports whether no setting is to be changed.
+func (s IGMPSettings) IsEmpty() bool {
+	return s.Snooping == nil && s.SnoopingVLAN == nil && s.BlockUnknownMulticast == nil &&
+		s.ValidateIGMPv3 == nil && s.RouterPorts == nil
+}
+
+// Validate checks the settings against the current configuration of the
+// device. Settings the device does not report are not supported by the
+// model.
+func (s IGMPSettings) Validate(current *IGMPConfig) error {
+	if (s.Snooping != nil || s.SnoopingVLAN != nil) && current.Snooping == nil {
+		return fmt.Errorf("IGMP snooping is not supported by this model")
+	}
+	if s.SnoopingVLAN != nil && (*s.SnoopingVLAN < 1 || *s.SnoopingVLAN > 4093) {
+		return fmt.Errorf("invalid IGMP snooping VLAN %d", *s.SnoopingVLAN)
+	}
+	if s.BlockUnknownMulticast != nil && current.BlockUnknownMulticast == nil {
+		return fmt.Errorf("blocking unknown multicast is not supported by this model")
+	}
+	if s.ValidateIGMPv3 != nil && current.ValidateIGMPv3 == nil {
+		return fmt.Errorf("IGMPv3 header validation is not supported by this model")
+	}
+	if s.RouterPorts != nil {
+		if current.BitmapWidth == 0 {
+			return fmt.Errorf("static router ports are not supported by this model")
+		}
+		if _, err := encodePortBitmap(*s.RouterPorts, current.BitmapWidth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Mismatches returns the settings the device does not report as expected,
+// for verifying a change.
+func (s IGMPSettings) Mismatches(config *IGMPConfig) []string {
+	var mismatches []string
+	if s.Snooping != nil && (config.Snooping == nil || *config.Snooping != *s.Snooping) {
+		mismatches = append(mismatches, "IGMP snooping")
+	}
+	if s.SnoopingVLAN != nil && config.SnoopingVLAN != *s.SnoopingVLAN {
+		mismatches = append(mismatches, "IGMP snooping VLAN")
+	}
+	if s.BlockUnknownMulticast != nil && (config.BlockUnknownMulticast == nil || *config.BlockUnknownMulticast != *s.BlockUnknownMulticast) {
+		mismatches = append(mismatches, "block unknown multicast")
+	}
+	if s.ValidateIGMPv3 != nil && (config.ValidateIGMPv3 == nil || *config.ValidateIGMPv3 != *s.ValidateIGMPv3) {
+		mismatches = append(mismatches, "validate IGMPv3")
+	}
+	if s.RouterPorts != nil && !equalPorts(sortedPorts(config.RouterPorts), sortedPorts(*s.RouterPorts)) {
+		mismatches = append(mismatches, "static router ports")
+	}
+	return mismatches
+}
+
+// values encodes the settings. The snooping state and its VLAN share one
+// parameter, so a change to either keeps the current value of the other.
+func (s IGMPSettings) values(current *IGMPConfig) ([]ParamValue, error) {
+	var values []ParamValue
+	if s.Snooping != nil || s.SnoopingVLAN != nil {
+		enabled := current.Snooping != nil && *current.Snooping
+		if s.Snooping != nil {
+			enabled = *s.Snooping
+		}
+		vlan := current.SnoopingVLAN
+		if s.SnoopingVLAN != nil {
+			vlan = *s.SnoopingVLAN
+		}
+		if vlan == 0 {
+			vlan = 1
+		}
+		values = append(values, ParamValue{Param: ParamIGMPSnooping, Value: EncodeIGMPSnooping(enabled, vlan)})
+	}
+	if s.BlockUnknownMulticast != nil {
+		values = append(values, ParamValue{Param: ParamBlockUnknownMcast, Value: []byte{boolByte(*s.BlockUnknownMulticast)}})
+	}
+	if s.ValidateIGMPv3 != nil {
+		values = append(values, ParamValue{Param: ParamValidateIGMPv3, Value: []byte{boolByte(*s.ValidateIGMPv3)}})
+	}
+	if s.RouterPorts != nil {
+		bitmap, err := encodePortBitmap(*s.RouterPorts, current.BitmapWidth)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, ParamValue{Param: ParamIGMPRouterPorts, Value: bitmap})
+	}
+	return values, nil
+}
+
+// ApplyIGMPSettings writes the given settings to a device in a single write
+// request. current is the configuration read before the change.
+func (c *Client) ApplyIGMPSettings(mac net.HardwareAddr, password string, current *IGMPConfig, settings IGMPSettings) error {
+	if settings.IsEmpty() {
+		return fmt.Errorf("no IGMP settings to change")
+	}
+	values, err := settings.values(current)
+	if err != nil {
+		return err
+	}
+	return c.WriteParams(mac, password, values...)
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 0x01
+	}
+	return 0x00
+}
@@ -0,0 +1,71 @@
+package nsdpclient
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeIGMPRouterPorts(t *testing.T) {
+	ports, err := DecodeIGMPRouterPorts([]byte{0x81, 0x40})
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !reflect.DeepEqual(ports, []uint8{1, 8, 10}) {
+		t.Errorf("Unexpected router ports: %v", ports)
+	}
+
+	if ports, _ := DecodeIGMPRouterPorts([]byte{0x00}); len(ports) != 0 {
+		t.Errorf("Expected no router ports, got %v", ports)
+	}
+	if _, err := DecodeIGMPRouterPorts(nil); err == nil {
+		t.Error("Expected error for empty value")
+	}
+}
+
+func TestEncodeIGMPSnooping(t *testing.T) {
+	if value := EncodeIGMPSnooping(true, 10); !reflect.DeepEqual(value, []byte{0x00, 0x01, 0x00, 0x0a}) {
+		t.Errorf("Unexpected value: %x", value)
+	}
+}
+
+func TestIGMPSettings(t *testing.T) {
+	enabled, disabled := true, false
+	current := &IGMPConfig{Snooping: &disabled, SnoopingVLAN: 1, ValidateIGMPv3: &disabled, BitmapWidth: 1}
+
+	vlan := uint16(20)
+	routerPorts := []uint8{8}
+	settings := IGMPSettings{SnoopingVLAN: &vlan, ValidateIGMPv3: &enabled, RouterPorts: &routerPorts}
+	if err := settings.Validate(current); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	values, err := settings.values(current)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	expected := []ParamValue{
+		{Param: ParamIGMPSnooping, Value: []byte{0x00, 0x00, 0x00, 0x14}},
+		{Param: ParamValidateIGMPv3, Value: []byte{0x01}},
+		{Param: ParamIGMPRouterPorts, Value: []byte{0x01}},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Unexpected values: %+v", values)
+	}
+
+	if mismatches := settings.Mismatches(current); len(mismatches) != 3 {
+		t.Errorf("Expected 3 mismatches, got %v", mismatches)
+	}
+	updated := &IGMPConfig{Snooping: &disabled, SnoopingVLAN: 20, ValidateIGMPv3: &enabled, RouterPorts: []uint8{8}, BitmapWidth: 1}
+	if mismatches := settings.Mismatches(updated); len(mismatches) != 0 {
+		t.Errorf("Unexpected mismatches: %v", mismatches)
+	}
+
+	for _, invalid := range []IGMPSettings{
+		{BlockUnknownMulticast: &enabled},
+		{RouterPorts: &[]uint8{9}},
+		{SnoopingVLAN: new(uint16)},
+	} {
+		if err := invalid.Validate(current); err == nil {
+			t.Errorf("Expected error for %+v", invalid)
+		}
+	}
+}
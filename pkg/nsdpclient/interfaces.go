@@ -0,0 +1,112 @@
+package nsdpclient
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// BroadcastTarget returns the NSDP target address for broadcasting on an
+// interface: the directed broadcast address of its first IPv4 network.
+// Connecting to it binds the connection to the interface's address, whereas
+// nsdp.IPv4BroadcastTarget leaves the choice of interface to the kernel.
+func BroadcastTarget(iface *net.Interface) (string, error) {
+	ipnet, err := interfaceIPv4Net(iface)
+	if err != nil {
+		return "", err
+	}
+	return TargetAddress(directedBroadcast(ipnet)), nil
+}
+
+// InterfaceAddress returns the first IPv4 address of an interface.
+func InterfaceAddress(iface *net.Interface) (net.IP, error) {
+	ipnet, err := interfaceIPv4Net(iface)
+	if err != nil {
+		return nil, err
+	}
+	return ipnet.IP.To4(), nil
+}
+
+// LocalAddressFor returns the local IPv4 address the kernel uses to reach a
+// remote address.
+func LocalAddressFor(remote net.IP) (net.IP, error) {
+	// Connecting a UDP socket selects the route without sending anything
+	conn, err := net.Dial("udp4", TargetAddress(remote))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP.To4(), nil
+}
+
+func interfaceIPv4Net(iface *net.Interface) (*net.IPNet, error) {
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get addresses of interface %s: %w", iface.Name, err)
+	}
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok || ipnet.IP.To4() == nil {
+			continue
+		}
+		return ipnet, nil
+	}
+	return nil, fmt.Errorf("interface %s has no IPv4 address", iface.Name)
+}
+
+// directedBroadcast returns the broadcast address of an IPv4 network.
+func directedBroadcast(ipnet *net.IPNet) net.IP {
+	ip := ipnet.IP.To4()
+	mask := ipnet.Mask
+	if len(mask) == net.IPv6len {
+		mask = mask[12:]
+	}
+	broadcast := make(net.IP, net.IPv4len)
+	for i := range ip {
+		broadcast[i] = ip[i] | ^mask[i]
+	}
+	return broadcast
+}
+
+// NewOnInterfaces creates a client broadcasting on each of the given
+// interfaces. Requests to a single device go out on the interface it
+// answered on during discovery.
+func NewOnInterfaces(ifaces []net.Interface, timeout time.Duration, verbose bool) (*Client, error) {
+	client := &Client{routes: make(map[string]*interfaceConn), verbose: verbose}
+	for i := range ifaces {
+		target, err := BroadcastTarget(&ifaces[i])
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+		conn, err := newConn(target, timeout, verbose)
+		if err != nil {
+			client.Close()
+			return nil, fmt.Errorf("interface %s: %w", ifaces[i].Name, err)
+		}
+		client.conns = append(client.conns, &interfaceConn{name: ifaces[i].Name, conn: conn})
+	}
+	if len(client.conns) == 0 {
+		return nil, fmt.Errorf("no network interface given")
+	}
+	return client, nil
+}
+
+// UsableInterfaces returns the interfaces NSDP can broadcast on: up, not a
+// loopback and with an IPv4 address.
+func UsableInterfaces() ([]net.Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+	var usable []net.Interface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if _, err := BroadcastTarget(&iface); err == nil {
+			usable = append(usable, iface)
+		}
+	}
+	return usable, nil
+}
@@ -0,0 +1,52 @@
+package nsdpclient
+
+import (
+	"net"
+	"testing"
+)
+
+func TestDirectedBroadcast(t *testing.T) {
+	tests := map[string]string{
+		"192.168.1.10/24": "192.168.1.255",
+		"10.1.0.3/16":     "10.1.255.255",
+		"172.16.5.1/30":   "172.16.5.3",
+	}
+	for cidr, expected := range tests {
+		ip, ipnet, _ := net.ParseCIDR(cidr)
+		ipnet.IP = ip
+		if got := directedBroadcast(ipnet).String(); got != expected {
+			t.Errorf("%s: expected %s, got %s", cidr, expected, got)
+		}
+	}
+}
+
+func TestBroadcastTargetLoopback(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatalf("Failed to list interfaces: %v", err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback == 0 {
+			continue
+		}
+		target, err := BroadcastTarget(&iface)
+		if err != nil {
+			t.Skipf("Loopback interface without IPv4 address: %v", err)
+		}
+		if target != "127.255.255.255:63322" {
+			t.Errorf("Unexpected loopback target: %s", target)
+		}
+		return
+	}
+	t.Skip("No loopback interface")
+}
+
+func TestLocalAddressFor(t *testing.T) {
+	ip, err := LocalAddressFor(net.ParseIP("127.0.0.1"))
+	if err != nil {
+		t.Fatalf("LocalAddressFor failed: %v", err)
+	}
+	if !ip.IsLoopback() {
+		t.Errorf("Expected a loopback address, got %s", ip)
+	}
+}
@@ -0,0 +1,119 @@
+package nsdpclient
+
+import (
+	"fmt"
+	"net"
+)
+
+// PortMirroring is the port mirroring configuration reported by the 0x5c00
+// parameter.
+type PortMirroring struct {
+	Destination uint8   // Port receiving the mirrored traffic (0: mirroring disabled)
+	Sources     []uint8 // Ports whose traffic is mirrored, ascending
+}
+
+// Enabled reports whether port mirroring is active.
+func (m PortMirroring) Enabled() bool {
+	return m.Destination != 0 && len(m.Sources) > 0
+}
+
+// DecodePortMirroring decodes the port mirroring parameter (0x5c00). The
+// value holds the destination port, a reserved byte and the bitmap of the
+// source ports, whose width depends on the port count.
+func DecodePortMirroring(value []byte) (PortMirroring, error) {
+	if len(value) < 3 {
+		return PortMirroring{}, fmt.Errorf("unexpected port mirroring length: %d", len(value))
+	}
+	return PortMirroring{
+		Destination: value[0],
+		Sources:     decodePortBitmap(value[2:]),
+	}, nil
+}
+
+// CheckPortMirroring returns warnings about a mirroring configuration: a
+// destination port that is mirrored itself, and a destination port carrying
+// VLAN traffic of its own, which is lost while the port only emits mirrored
+// frames. That is traffic of VLANs the destination is a tagged member of, or
+// an untagged member of while no source is, so sharing the sources' untagged
+// VLAN (usually the default VLAN 1) is not reported. memberships may be nil
+// if the VLAN layout is unknown.
+func CheckPortMirroring(m PortMirroring, memberships []VLANMembership) []string {
+	if !m.Enabled() {
+		return nil
+	}
+
+	var warnings []string
+	if containsPort(m.Sources, m.Destination) {
+		warnings = append(warnings, fmt.Sprintf("destination port %d is also a mirror source", m.Destination))
+	}
+
+	var vlans []uint16
+	for _, membership := range memberships {
+		if !membership.IsMember(m.Destination) {
+			continue
+		}
+		if membership.IsTagged(m.Destination) || !hasUntaggedMember(membership, m.Sources) {
+			vlans = append(vlans, membership.ID)
+		}
+	}
+	if len(vlans) > 0 {
+		warnings = append(warnings, fmt.Sprintf("destination port %d is a member of VLAN(s) %v; that traffic is lost while mirroring", m.Destination, vlans))
+	}
+	return warnings
+}
+
+func hasUntaggedMember(membership VLANMembership, ports []uint8) bool {
+	for _, port := range ports {
+		if membership.IsMember(port) && !membership.IsTagged(port) {
+			return true
+		}
+	}
+	return false
+}
+
+// EncodePortMirroring encodes a mirroring configuration as a 0x5c00 value
+// with a source bitmap of the given width. A configuration without
+// destination or sources disables mirroring.
+func EncodePortMirroring(m PortMirroring, width int) ([]byte, error) {
+	if !m.Enabled() {
+		return make([]byte, 2+width), nil
+	}
+	if int(m.Destination) > width*8 {
+		return nil, fmt.Errorf("destination port %d out of range", m.Destination)
+	}
+	if containsPort(m.Sources, m.Destination) {
+		return nil, fmt.Errorf("destination port %d cannot be a mirror source", m.Destination)
+	}
+	sources, err := encodePortBitmap(m.Sources, width)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{m.Destination, 0x00}, sources...), nil
+}
+
+// PortMirroring reads the mirroring configuration of a device along with the
+// width of its source bitmap in bytes.
+func (c *Client) PortMirroring(mac net.HardwareAddr) (PortMirroring, int, error) {
+	value, err := c.ReadParam(mac, ParamPortMirroring)
+	if err != nil {
+		return PortMirroring{}, 0, err
+	}
+	if value == nil {
+		return PortMirroring{}, 0, fmt.Errorf("port mirroring not reported by the device")
+	}
+	m, err := DecodePortMirroring(value)
+	if err != nil {
+		return PortMirroring{}, 0, err
+	}
+	return m, len(value) - 2, nil
+}
+
+// SetPortMirroring writes a mirroring configuration, or disables mirroring if
+// it has no destination or sources.
+func (c *Client) SetPortMirroring(mac net.HardwareAddr, password string, m PortMirroring, width int) error {
+	value, err := EncodePortMirroring(m, width)
+	if err != nil {
+		return err
+	}
+	return c.WriteParams(mac, password, ParamValue{Param: ParamPortMirroring, Value: value})
+}
@@ -0,0 +1,82 @@
+package nsdpclient
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodePortMirroring(t *testing.T) {
+	m, err := DecodePortMirroring([]byte{0x08, 0x00, 0x61})
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !m.Enabled() || m.Destination != 8 {
+		t.Errorf("Expected mirroring to port 8, got %+v", m)
+	}
+	if !reflect.DeepEqual(m.Sources, []uint8{2, 3, 8}) {
+		t.Errorf("Unexpected sources: %v", m.Sources)
+	}
+
+	m, err = DecodePortMirroring([]byte{0x00, 0x00, 0x00, 0x00})
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if m.Enabled() {
+		t.Errorf("Expected mirroring to be disabled, got %+v", m)
+	}
+
+	if _, err := DecodePortMirroring([]byte{0x08}); err == nil {
+		t.Error("Expected error for short value")
+	}
+}
+
+func TestCheckPortMirroring(t *testing.T) {
+	m := PortMirroring{Destination: 8, Sources: []uint8{2, 3, 8}}
+	memberships := []VLANMembership{
+		{ID: 1, Members: []uint8{1, 2, 3, 4, 5, 6, 7}},
+		{ID: 10, Members: []uint8{7, 8}, Tagged: []uint8{8}},
+	}
+
+	warnings := CheckPortMirroring(m, memberships)
+	if len(warnings) != 2 {
+		t.Fatalf("Expected 2 warnings, got %v", warnings)
+	}
+
+	// Sharing the sources' untagged VLAN loses no traffic
+	m.Sources = []uint8{2, 3}
+	shared := []VLANMembership{{ID: 1, Members: []uint8{1, 2, 3, 4, 5, 6, 7, 8}}}
+	if warnings := CheckPortMirroring(m, shared); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	// An untagged VLAN of the destination alone does
+	shared = append(shared, VLANMembership{ID: 20, Members: []uint8{8}})
+	if warnings := CheckPortMirroring(m, shared); len(warnings) != 1 || !strings.Contains(warnings[0], "[20]") {
+		t.Errorf("Expected a warning for VLAN 20, got %v", warnings)
+	}
+}
+
+func TestEncodePortMirroring(t *testing.T) {
+	value, err := EncodePortMirroring(PortMirroring{Destination: 8, Sources: []uint8{2, 3}}, 1)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !reflect.DeepEqual(value, []byte{0x08, 0x00, 0x60}) {
+		t.Errorf("Unexpected value: %x", value)
+	}
+
+	if value, _ := EncodePortMirroring(PortMirroring{}, 2); !reflect.DeepEqual(value, []byte{0x00, 0x00, 0x00, 0x00}) {
+		t.Errorf("Unexpected disabled value: %x", value)
+	}
+
+	for _, invalid := range []PortMirroring{
+		{Destination: 9, Sources: []uint8{1}},
+		{Destination: 2, Sources: []uint8{2}},
+		{Destination: 1, Sources: []uint8{9}},
+	} {
+		if _, err := EncodePortMirroring(invalid, 1); err == nil {
+			t.Errorf("Expected error for %+v", invalid)
+		}
+	}
+}
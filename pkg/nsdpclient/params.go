@@ -0,0 +1,149 @@
+package nsdpclient
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NSDP parameter constants from the documentation
+const (
+	// System/Status parameters
+	ParamPortStatus        = 0x0c00 // Port link status/speed
+	ParamPortStatistics    = 0x1000 // Port statistics
+	ParamAvailablePorts    = 0x6000 // Number of available ports
+	ParamCableTest         = 0x1800 // Start cable test (write only)
+	ParamCableTesterResult = 0x1c00 // Cable test results
+	ParamPortMirroring     = 0x5c00 // Port mirroring configuration
+	ParamUnknown8C00       = 0x8c00 // Unknown parameter
+
+	// IGMP Snooping parameters
+	//
+	// Deprecated: ParamIGMPUnknown8000 is the same code as ParamIGMPRouterPorts;
+	// see Interpretations for the known meanings of 0x8000.
+	ParamIGMPUnknown8000   = 0x8000
+	ParamIGMPSnooping      = 0x6800 // IGMP snooping status
+	ParamBlockUnknownMcast = 0x6c00 // Block unknown multicast
+	ParamValidateIGMPv3    = 0x7000 // Validate IGMPv3 IP header
+	ParamIGMPRouterPorts   = 0x8000 // IGMP snooping static router ports
+
+	// Loop Detection
+	ParamLoopDetection = 0x9000 // Loop detection status
+
+	// VLAN parameters
+	ParamVLANEngine     = 0x2000 // VLAN engine mode
+	ParamVLANMembership = 0x2400 // VLAN port membership (port-based)
+	ParamVLAN8021Q      = 0x2800 // 802.1Q VLAN membership
+	ParamVLANPVID       = 0x3000 // 802.1Q default VLAN ID (PVID)
+	ParamVLANDelete     = 0x2c00 // Delete 802.1Q VLAN (write only)
+	ParamVLANUnknown    = 0x6400 // Unknown VLAN parameter
+
+	// QoS parameters
+	ParamQoSEngine      = 0x3400 // QoS engine mode
+	ParamQoSPriority    = 0x3800 // QoS port priority
+	ParamIngressLimit   = 0x4c00 // Ingress rate limit
+	ParamEgressLimit    = 0x5000 // Egress rate limit
+	ParamBcastFiltering = 0x5400 // Broadcast filtering
+	ParamStormControl   = 0x5800 // Storm control bandwidth
+)
+
+// ParamInterpretation is one known meaning of a parameter code. Some codes
+// are used differently depending on the switch model or on the request they
+// appear in, so a code may have several interpretations.
+type ParamInterpretation struct {
+	Description string
+	Context     string   // Command area the interpretation applies to (e.g. "igmp"); empty for any
+	Models      []string // Model name prefixes the interpretation applies to; empty for all models
+}
+
+// matches reports whether the interpretation applies to the given context
+// and model. Empty arguments match any interpretation.
+func (i ParamInterpretation) matches(context, model string) bool {
+	if context != "" && i.Context != "" && i.Context != context {
+		return false
+	}
+	if model == "" || len(i.Models) == 0 {
+		return true
+	}
+	for _, prefix := range i.Models {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Known parameter interpretations. Where a code has several, the more
+// specific ones come first.
+var paramRegistry = map[uint16][]ParamInterpretation{
+	ParamPortStatus:         {{Description: "Port Status (Link/Speed)"}},
+	ParamPortStatistics:     {{Description: "Port Statistics"}},
+	ParamAvailablePorts:     {{Description: "Available Ports Count"}},
+	ParamPassword:           {{Description: "Admin Password"}},
+	ParamPasswordEncryption: {{Description: "Password Encryption"}},
+	ParamPasswordSalt:       {{Description: "Password Salt"}},
+	ParamPasswordHash:       {{Description: "Admin Password Hash"}},
+	ParamFirmwareUpgrade:    {{Description: "Firmware Upgrade"}},
+	ParamReboot:             {{Description: "Reboot"}},
+	ParamFactoryReset:       {{Description: "Factory Reset"}},
+	ParamResetPortStats:     {{Description: "Reset Port Statistics"}},
+	ParamCableTest:          {{Description: "Cable Test"}},
+	ParamCableTesterResult:  {{Description: "Cable Tester Results"}},
+	ParamPortMirroring:      {{Description: "Port Mirroring Configuration"}},
+	ParamUnknown8C00:        {{Description: "Unknown Parameter (0x8c00)"}},
+	ParamIGMPSnooping:       {{Description: "IGMP Snooping Status"}},
+	ParamBlockUnknownMcast:  {{Description: "Block Unknown Multicast"}},
+	ParamValidateIGMPv3:     {{Description: "Validate IGMPv3 IP Header"}},
+	ParamIGMPRouterPorts: {
+		{Description: "IGMP Router Ports", Context: "igmp"},
+		{Description: "Unknown IGMP Parameter (0x8000)"},
+	},
+	ParamLoopDetection:  {{Description: "Loop Detection"}},
+	ParamVLANEngine:     {{Description: "VLAN Engine Mode"}},
+	ParamVLANMembership: {{Description: "VLAN Port Membership"}},
+	ParamVLAN8021Q:      {{Description: "802.1Q VLAN Membership"}},
+	ParamVLANPVID:       {{Description: "802.1Q PVID"}},
+	ParamVLANDelete:     {{Description: "Delete 802.1Q VLAN"}},
+	ParamVLANUnknown:    {{Description: "Unknown VLAN Parameter (0x6400)"}},
+	ParamQoSEngine:      {{Description: "QoS Engine Mode"}},
+	ParamQoSPriority:    {{Description: "QoS Port Priority"}},
+	ParamIngressLimit:   {{Description: "Ingress Rate Limit"}},
+	ParamEgressLimit:    {{Description: "Egress Rate Limit"}},
+	ParamBcastFiltering: {{Description: "Broadcast Filtering"}},
+	ParamStormControl:   {{Description: "Storm Control Bandwidth"}},
+}
+
+// Interpretations returns all known interpretations of a parameter code
+// applying to the given context and model. Empty arguments match any
+// context or model.
+func Interpretations(param uint16, context, model string) []ParamInterpretation {
+	var matching []ParamInterpretation
+	for _, interpretation := range paramRegistry[param] {
+		if interpretation.matches(context, model) {
+			matching = append(matching, interpretation)
+		}
+	}
+	return matching
+}
+
+// DescribeParam returns the description of the most specific interpretation
+// of a parameter code for the given context and model.
+func DescribeParam(param uint16, context, model string) string {
+	if interpretations := Interpretations(param, context, model); len(interpretations) > 0 {
+		return interpretations[0].Description
+	}
+	return fmt.Sprintf("Parameter 0x%04x", param)
+}
+
+// ParamDescription returns a human readable name for the given parameter
+// code. Codes with several interpretations list all of them.
+func ParamDescription(param uint16) string {
+	interpretations := paramRegistry[param]
+	if len(interpretations) == 0 {
+		return fmt.Sprintf("Parameter 0x%04x", param)
+	}
+	descriptions := make([]string, 0, len(interpretations))
+	for _, interpretation := range interpretations {
+		descriptions = append(descriptions, interpretation.Description)
+	}
+	return strings.Join(descriptions, " / ")
+}
@@ -0,0 +1,107 @@
+package nsdpclient
+
+import (
+	"net"
+	"sort"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+// PortReport holds the status and traffic counters of all ports of a device
+// as collected by a single request.
+type PortReport struct {
+	PortCount  int                   // Number of ports reported by the device (0: unknown)
+	Status     []*nsdp.PortStatus    // Ordered by port
+	Statistics []*nsdp.PortStatistic // Ordered by port
+}
+
+// Count returns the number of ports of the device. If the device did not
+// report its port count, the highest port with a status or statistics record
+// is used instead.
+func (r *PortReport) Count() int {
+	count := r.PortCount
+	for _, ps := range r.Status {
+		if int(ps.Port) > count {
+			count = int(ps.Port)
+		}
+	}
+	for _, stat := range r.Statistics {
+		if int(stat.Port) > count {
+			count = int(stat.Port)
+		}
+	}
+	return count
+}
+
+// StatusOf returns the status of a port, or nil if the device reported none.
+func (r *PortReport) StatusOf(port uint8) *nsdp.PortStatus {
+	for _, ps := range r.Status {
+		if ps.Port == port {
+			return ps
+		}
+	}
+	return nil
+}
+
+// StatisticOf returns the counters of a port, or nil if the device reported
+// none.
+func (r *PortReport) StatisticOf(port uint8) *nsdp.PortStatistic {
+	for _, stat := range r.Statistics {
+		if stat.Port == port {
+			return stat
+		}
+	}
+	return nil
+}
+
+// Ports reads the port count plus the status and counters of every port of
+// a device in one round-trip. The device answers with one record per port.
+func (c *Client) Ports(mac net.HardwareAddr) (*PortReport, error) {
+	responseMsg, err := c.sendReceive(mac, []nsdp.TLV{
+		&nsdp.GenericTLV{Type: ParamAvailablePorts},
+		nsdp.EmptyPortStatus(),
+		nsdp.EmptyPortStatistic(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	report := c.newPortReport(responseMsg.Body)
+	c.logf("Found %d port status and %d port statistics record(s)", len(report.Status), len(report.Statistics))
+	return report, nil
+}
+
+// newPortReport collects the port records of a response. Statistics records
+// the library passes on undecoded are decoded with DecodePortStatistic.
+func (c *Client) newPortReport(body []nsdp.TLV) *PortReport {
+	report := &PortReport{}
+	for _, tlv := range body {
+		switch v := tlv.(type) {
+		case *nsdp.PortStatus:
+			report.Status = append(report.Status, v)
+		case *nsdp.PortStatistic:
+			report.Statistics = append(report.Statistics, v)
+		default:
+			param, value, ok := rawTLV(tlv)
+			if !ok {
+				continue
+			}
+			switch {
+			case param == ParamAvailablePorts && len(value) >= 1:
+				report.PortCount = int(value[0])
+			case param == ParamPortStatistics:
+				if stat, err := DecodePortStatistic(value); err == nil {
+					report.Statistics = append(report.Statistics, stat)
+				} else {
+					c.logf("Skipping port statistics record: %v", err)
+				}
+			}
+		}
+	}
+	sort.Slice(report.Status, func(i, j int) bool {
+		return report.Status[i].Port < report.Status[j].Port
+	})
+	sort.Slice(report.Statistics, func(i, j int) bool {
+		return report.Statistics[i].Port < report.Statistics[j].Port
+	})
+	return report
+}
@@ -0,0 +1,83 @@
+package nsdpclient
+
+import (
+	"net"
+	"testing"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+func TestPorts(t *testing.T) {
+	client := newTestClient(t, testDeviceResponse)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	report, err := client.Ports(mac)
+	if err != nil {
+		t.Fatalf("Ports failed: %v", err)
+	}
+	if len(report.Status) != 8 || len(report.Statistics) != 8 {
+		t.Fatalf("Expected 8 status and statistics records, got %d and %d", len(report.Status), len(report.Statistics))
+	}
+	if report.PortCount != 0 || report.Count() != 8 {
+		t.Errorf("Expected port count 8 derived from the records, got %d (reported %d)", report.Count(), report.PortCount)
+	}
+	if stat := report.StatisticOf(3); stat == nil || stat.Received != 0x039bd6ce {
+		t.Errorf("Unexpected port 3 statistics: %+v", stat)
+	}
+	if report.StatusOf(9) != nil {
+		t.Error("Expected no status for port 9")
+	}
+}
+
+func TestPortsReportedCount(t *testing.T) {
+	client := newTestClient(t, testResponse(nsdp.ReadResponse, 0,
+		&nsdp.GenericTLV{Type: ParamAvailablePorts, Length: 1, Value: []byte{48}},
+		nsdp.NewPortStatus(2, 5),
+		nsdp.NewPortStatus(1, 0),
+	))
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	report, err := client.Ports(mac)
+	if err != nil {
+		t.Fatalf("Ports failed: %v", err)
+	}
+	if report.Count() != 48 {
+		t.Errorf("Expected 48 ports, got %d", report.Count())
+	}
+	if len(report.Status) != 2 || report.Status[0].Port != 1 {
+		t.Errorf("Expected status records ordered by port, got %+v", report.Status)
+	}
+}
+
+func TestNewPortReportGenericStatistics(t *testing.T) {
+	value := make([]byte, portStatisticLen)
+	value[0] = 2
+	value[8] = 0x40 // 64 bytes received
+
+	report := (&Client{}).newPortReport([]nsdp.TLV{
+		nsdp.NewPortStatistic(3, 1, 2, 3, 4, 5, 6),
+		&nsdp.GenericTLV{Type: ParamPortStatistics, Length: uint16(len(value)), Value: value},
+		&nsdp.GenericTLV{Type: ParamPortStatistics, Length: 3, Value: []byte{0x01, 0x00, 0x00}},
+	})
+	if len(report.Statistics) != 2 || report.Statistics[0].Port != 2 || report.Statistics[0].Received != 64 {
+		t.Errorf("Expected the generic record decoded and ordered first, got %+v", report.Statistics)
+	}
+}
+
+func TestPortStatistics(t *testing.T) {
+	client := newTestClient(t, testResponse(nsdp.ReadResponse, 0,
+		nsdp.NewPortStatistic(3, 1, 2, 3, 4, 5, 6),
+		nsdp.NewPortStatistic(1, 1, 2, 3, 4, 5, 6),
+		// A malformed record arrives as a generic TLV and is skipped
+		&nsdp.GenericTLV{Type: ParamPortStatistics, Length: 3, Value: []byte{0x02, 0x00, 0x00}},
+	))
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	stats, err := client.PortStatistics(mac)
+	if err != nil {
+		t.Fatalf("PortStatistics failed: %v", err)
+	}
+	if len(stats) != 2 || stats[0].Port != 1 || stats[1].Port != 3 {
+		t.Errorf("Expected the records of ports 1 and 3 ordered by port, got %+v", stats)
+	}
+}
@@ -0,0 +1,287 @@
+package nsdpclient
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// PortPriority is the QoS priority of a port as reported by the 0x3800
+// parameter (1: high, 2: medium, 3: normal, 4: low).
+type PortPriority struct {
+	Port     uint8
+	Priority uint8
+}
+
+// DecodeQoSPriority decodes the records of the QoS port priority parameter
+// (0x3800). Each record holds the port number followed by its priority.
+func DecodeQoSPriority(records [][]byte) ([]PortPriority, error) {
+	priorities := make([]PortPriority, 0, len(records))
+	for _, record := range records {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("unexpected QoS priority record length: %d", len(record))
+		}
+		priorities = append(priorities, PortPriority{Port: record[0], Priority: record[1]})
+	}
+	return priorities, nil
+}
+
+// PortRateLimit is the bandwidth limit of a port as reported by the ingress
+// (0x4c00) and egress (0x5000) rate limit parameters and the storm control
+// parameter (0x5800), which caps broadcast traffic. Limit is an index into
+// the switch's rate table (0: no limit, 1: 512 Kbps, 2: 1 Mbps, ...).
+type PortRateLimit struct {
+	Port  uint8
+	Limit uint16
+}
+
+// DecodeRateLimit decodes the records of a rate limit or storm control
+// parameter. Each record holds the port number, two reserved bytes and the
+// 16 bit limit; firmware omitting the reserved bytes is accepted as well.
+func DecodeRateLimit(records [][]byte) ([]PortRateLimit, error) {
+	limits := make([]PortRateLimit, 0, len(records))
+	for _, record := range records {
+		if len(record) != 5 && len(record) != 3 {
+			return nil, fmt.Errorf("unexpected rate limit record length: %d", len(record))
+		}
+		limits = append(limits, PortRateLimit{
+			Port:  record[0],
+			Limit: binary.BigEndian.Uint16(record[len(record)-2:]),
+		})
+	}
+	return limits, nil
+}
+
+// QoS engine modes of the 0x3400 parameter
+const (
+	QoSEnginePortBased uint8 = 0x01
+	QoSEngine8021p     uint8 = 0x02
+)
+
+// Highest index of the rate table used by rate limits and storm control
+const MaxRateLimit uint16 = 11
+
+// QoSConfig is the QoS, rate limit and storm control configuration of a
+// device.
+type QoSConfig struct {
+	Engine             *uint8 // nil if not reported
+	Priorities         []PortPriority
+	Ingress            []PortRateLimit
+	Egress             []PortRateLimit
+	StormControl       []PortRateLimit
+	BroadcastFiltering *uint8 // nil if not reported
+
+	// Record length of the rate limit and storm control parameters as
+	// reported by the device (3 or 5), which writes have to use as well
+	RateLimitRecordLen map[uint16]int
+}
+
+// QoSConfig reads the QoS configuration of a device in one request.
+func (c *Client) QoSConfig(mac net.HardwareAddr) (*QoSConfig, error) {
+	params, err := c.ReadParams(mac, ParamQoSEngine, ParamQoSPriority, ParamIngressLimit, ParamEgressLimit, ParamBcastFiltering, ParamStormControl)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &QoSConfig{RateLimitRecordLen: make(map[uint16]int)}
+	if engine := params.Get(ParamQoSEngine); len(engine) >= 1 {
+		config.Engine = &engine[0]
+	}
+	if filtering := params.Get(ParamBcastFiltering); len(filtering) >= 1 {
+		config.BroadcastFiltering = &filtering[0]
+	}
+	if config.Priorities, err = DecodeQoSPriority(params.Records(ParamQoSPriority)); err != nil {
+		return nil, err
+	}
+	for param, limits := range map[uint16]*[]PortRateLimit{
+		ParamIngressLimit: &config.Ingress,
+		ParamEgressLimit:  &config.Egress,
+		ParamStormControl: &config.StormControl,
+	} {
+		records := params.Records(param)
+		if *limits, err = DecodeRateLimit(records); err != nil {
+			return nil, err
+		}
+		if len(records) > 0 {
+			config.RateLimitRecordLen[param] = len(records[0])
+		}
+	}
+	return config, nil
+}
+
+// EncodeRateLimit encodes a port rate limit as a record of the rate limit or
+// storm control parameters, with the reserved bytes unless recordLen is 3.
+func EncodeRateLimit(limit PortRateLimit, recordLen int) []byte {
+	record := []byte{limit.Port, 0, 0, 0, 0}
+	if recordLen == 3 {
+		record = record[:3]
+	}
+	binary.BigEndian.PutUint16(record[len(record)-2:], limit.Limit)
+	return record
+}
+
+// EncodeQoSPriority encodes a port priority as a 0x3800 record.
+func EncodeQoSPriority(priority PortPriority) []byte {
+	return []byte{priority.Port, priority.Priority}
+}
+
+// SetQoSEngine switches the QoS engine mode.
+func (c *Client) SetQoSEngine(mac net.HardwareAddr, password string, mode uint8) error {
+	return c.WriteParams(mac, password, ParamValue{Param: ParamQoSEngine, Value: []byte{mode}})
+}
+
+// SetBroadcastFiltering enables or disables broadcast filtering, which
+// enforces the storm control limits.
+func (c *Client) SetBroadcastFiltering(mac net.HardwareAddr, password string, enabled bool) error {
+	value := byte(0x00)
+	if enabled {
+		value = 0x03
+	}
+	return c.WriteParams(mac, password, ParamValue{Param: ParamBcastFiltering, Value: []byte{value}})
+}
+
+// PortQoSSettings holds per-port QoS values to change. Nil fields are left
+// unchanged.
+type PortQoSSettings struct {
+	Priority     *uint8
+	Ingress      *uint16
+	Egress       *uint16
+	StormControl *uint16
+}
+
+// IsEmpty reports whether no value is to be changed.
+func (s PortQoSSettings) IsEmpty() bool {
+	return s.Priority == nil && s.Ingress == nil && s.Egress == nil && s.StormControl == nil
+}
+
+// Validate checks the settings against the current configuration of the
+// device. A parameter the device does not report, or a port it reports no
+// value for, is not supported by the model.
+func (s PortQoSSettings) Validate(config *QoSConfig, ports []uint8) error {
+	if len(ports) == 0 {
+		return fmt.Errorf("no ports given")
+	}
+	if s.Priority != nil {
+		if *s.Priority < 1 || *s.Priority > 4 {
+			return fmt.Errorf("invalid priority %d", *s.Priority)
+		}
+		if config.Engine != nil && *config.Engine != QoSEnginePortBased {
+			return fmt.Errorf("per-port priority requires the port based QoS engine")
+		}
+		for _, port := range ports {
+			if !hasPriority(config.Priorities, port) {
+				return fmt.Errorf("port %d has no QoS priority on this model", port)
+			}
+		}
+	}
+	for _, limit := range []struct {
+		name   string
+		value  *uint16
+		limits []PortRateLimit
+	}{
+		{"ingress limit", s.Ingress, config.Ingress},
+		{"egress limit", s.Egress, config.Egress},
+		{"storm control", s.StormControl, config.StormControl},
+	} {
+		if limit.value == nil {
+			continue
+		}
+		if *limit.value > MaxRateLimit {
+			return fmt.Errorf("invalid %s %d", limit.name, *limit.value)
+		}
+		if len(limit.limits) == 0 {
+			return fmt.Errorf("%s is not supported by this model", limit.name)
+		}
+		for _, port := range ports {
+			if !hasRateLimit(limit.limits, port) {
+				return fmt.Errorf("port %d has no %s on this model", port, limit.name)
+			}
+		}
+	}
+	return nil
+}
+
+// Mismatches returns the values the device does not report as expected, for
+// verifying a change.
+func (s PortQoSSettings) Mismatches(config *QoSConfig, ports []uint8) []string {
+	var mismatches []string
+	for _, port := range ports {
+		if s.Priority != nil && !containsPriority(config.Priorities, PortPriority{Port: port, Priority: *s.Priority}) {
+			mismatches = append(mismatches, fmt.Sprintf("port %d: priority not changed", port))
+		}
+		for _, limit := range []struct {
+			name   string
+			value  *uint16
+			limits []PortRateLimit
+		}{
+			{"ingress limit", s.Ingress, config.Ingress},
+			{"egress limit", s.Egress, config.Egress},
+			{"storm control", s.StormControl, config.StormControl},
+		} {
+			if limit.value != nil && !containsRateLimit(limit.limits, PortRateLimit{Port: port, Limit: *limit.value}) {
+				mismatches = append(mismatches, fmt.Sprintf("port %d: %s not changed", port, limit.name))
+			}
+		}
+	}
+	return mismatches
+}
+
+// SetPortQoS writes the given values for each of the ports in a single write
+// request. Rate limits use the record length config was read with.
+func (c *Client) SetPortQoS(mac net.HardwareAddr, password string, config *QoSConfig, ports []uint8, settings PortQoSSettings) error {
+	if settings.IsEmpty() {
+		return fmt.Errorf("no QoS settings to change")
+	}
+	var values []ParamValue
+	for _, port := range ports {
+		if settings.Priority != nil {
+			values = append(values, ParamValue{Param: ParamQoSPriority, Value: EncodeQoSPriority(PortPriority{Port: port, Priority: *settings.Priority})})
+		}
+		if settings.Ingress != nil {
+			values = append(values, ParamValue{Param: ParamIngressLimit, Value: EncodeRateLimit(PortRateLimit{Port: port, Limit: *settings.Ingress}, config.RateLimitRecordLen[ParamIngressLimit])})
+		}
+		if settings.Egress != nil {
+			values = append(values, ParamValue{Param: ParamEgressLimit, Value: EncodeRateLimit(PortRateLimit{Port: port, Limit: *settings.Egress}, config.RateLimitRecordLen[ParamEgressLimit])})
+		}
+		if settings.StormControl != nil {
+			values = append(values, ParamValue{Param: ParamStormControl, Value: EncodeRateLimit(PortRateLimit{Port: port, Limit: *settings.StormControl}, config.RateLimitRecordLen[ParamStormControl])})
+		}
+	}
+	return c.WriteParams(mac, password, values...)
+}
+
+func hasPriority(priorities []PortPriority, port uint8) bool {
+	for _, p := range priorities {
+		if p.Port == port {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPriority(priorities []PortPriority, priority PortPriority) bool {
+	for _, p := range priorities {
+		if p == priority {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRateLimit(limits []PortRateLimit, port uint8) bool {
+	for _, l := range limits {
+		if l.Port == port {
+			return true
+		}
+	}
+	return false
+}
+
+func containsRateLimit(limits []PortRateLimit, limit PortRateLimit) bool {
+	for _, l := range limits {
+		if l == limit {
+			return true
+		}
+	}
+	return false
+}
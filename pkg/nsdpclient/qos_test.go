@@ -0,0 +1,144 @@
+package nsdpclient
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+func TestDecodeQoSPriority(t *testing.T) {
+	priorities, err := DecodeQoSPriority([][]byte{{0x01, 0x01}, {0x02, 0x04}})
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	expected := []PortPriority{{Port: 1, Priority: 1}, {Port: 2, Priority: 4}}
+	if !reflect.DeepEqual(priorities, expected) {
+		t.Errorf("Expected %v, got %v", expected, priorities)
+	}
+
+	if _, err := DecodeQoSPriority([][]byte{{0x01}}); err == nil {
+		t.Error("Expected error for short record")
+	}
+}
+
+func TestDecodeRateLimit(t *testing.T) {
+	limits, err := DecodeRateLimit([][]byte{
+		{0x01, 0x00, 0x00, 0x00, 0x05},
+		{0x02, 0x00, 0x00, 0x00, 0x00},
+		{0x03, 0x00, 0x0b},
+	})
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	expected := []PortRateLimit{{Port: 1, Limit: 5}, {Port: 2, Limit: 0}, {Port: 3, Limit: 11}}
+	if !reflect.DeepEqual(limits, expected) {
+		t.Errorf("Expected %v, got %v", expected, limits)
+	}
+
+	if _, err := DecodeRateLimit([][]byte{{0x01, 0x00, 0x00, 0x05}}); err == nil {
+		t.Error("Expected error for invalid record length")
+	}
+}
+
+func TestDecodeStormControl(t *testing.T) {
+	// Storm control shares the rate limit record layout
+	limits, err := DecodeRateLimit([][]byte{{0x04, 0x00, 0x00, 0x00, 0x03}})
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if len(limits) != 1 || limits[0].Port != 4 || limits[0].Limit != 3 {
+		t.Errorf("Unexpected storm control limits: %v", limits)
+	}
+}
+
+func TestEncodeRateLimit(t *testing.T) {
+	for _, recordLen := range []int{3, 5} {
+		record := EncodeRateLimit(PortRateLimit{Port: 3, Limit: 5}, recordLen)
+		if len(record) != recordLen {
+			t.Errorf("Expected a %d byte record, got % x", recordLen, record)
+		}
+		limits, err := DecodeRateLimit([][]byte{record})
+		if err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		if limits[0] != (PortRateLimit{Port: 3, Limit: 5}) {
+			t.Errorf("Unexpected round trip result: %+v", limits[0])
+		}
+	}
+}
+
+func TestPortQoSSettingsValidate(t *testing.T) {
+	portBased := QoSEnginePortBased
+	dot1p := QoSEngine8021p
+	config := &QoSConfig{
+		Engine:     &portBased,
+		Priorities: []PortPriority{{Port: 1, Priority: 3}, {Port: 2, Priority: 3}},
+		Ingress:    []PortRateLimit{{Port: 1}, {Port: 2}},
+		Egress:     []PortRateLimit{{Port: 1}, {Port: 2}},
+	}
+	high := uint8(1)
+	invalidPriority := uint8(7)
+	limit := uint16(5)
+	invalidLimit := uint16(12)
+
+	tests := []struct {
+		name     string
+		settings PortQoSSettings
+		ports    []uint8
+		valid    bool
+	}{
+		{"priority", PortQoSSettings{Priority: &high}, []uint8{1, 2}, true},
+		{"ingress and egress", PortQoSSettings{Ingress: &limit, Egress: &limit}, []uint8{2}, true},
+		{"invalid priority", PortQoSSettings{Priority: &invalidPriority}, []uint8{1}, false},
+		{"invalid limit", PortQoSSettings{Ingress: &invalidLimit}, []uint8{1}, false},
+		{"port beyond model", PortQoSSettings{Ingress: &limit}, []uint8{9}, false},
+		{"unsupported storm control", PortQoSSettings{StormControl: &limit}, []uint8{1}, false},
+		{"no ports", PortQoSSettings{Ingress: &limit}, nil, false},
+	}
+	for _, tt := range tests {
+		err := tt.settings.Validate(config, tt.ports)
+		if tt.valid != (err == nil) {
+			t.Errorf("%s: unexpected result %v", tt.name, err)
+		}
+	}
+
+	config.Engine = &dot1p
+	if err := (PortQoSSettings{Priority: &high}).Validate(config, []uint8{1}); err == nil {
+		t.Error("Expected error for priority with the 802.1p engine")
+	}
+}
+
+func TestSetPortQoSRecordLen(t *testing.T) {
+	client, requests := newRecordingClient(t,
+		testResponse(nsdp.ReadResponse, 0,
+			&nsdp.GenericTLV{Type: ParamIngressLimit, Length: 3, Value: []byte{0x01, 0x00, 0x00}},
+			&nsdp.GenericTLV{Type: ParamEgressLimit, Length: 5, Value: []byte{0x01, 0x00, 0x00, 0x00, 0x00}},
+		),
+		testResponse(nsdp.WriteResponse, 0),
+	)
+	client.SetPasswordScheme(PasswordPlain)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	config, err := client.QoSConfig(mac)
+	if err != nil {
+		t.Fatalf("QoSConfig failed: %v", err)
+	}
+	nextRequest(t, requests)
+	limit := uint16(4)
+	if err := client.SetPortQoS(mac, "password", config, []uint8{1}, PortQoSSettings{Ingress: &limit, Egress: &limit}); err != nil {
+		t.Fatalf("SetPortQoS failed: %v", err)
+	}
+
+	// Each parameter is written in the length the device reported it with
+	_, tlvs := nextRequest(t, requests)
+	expected := []ParamValue{
+		{Param: ParamPassword, Value: []byte("password")},
+		{Param: ParamIngressLimit, Value: []byte{0x01, 0x00, 0x04}},
+		{Param: ParamEgressLimit, Value: []byte{0x01, 0x00, 0x00, 0x00, 0x04}},
+	}
+	if !reflect.DeepEqual(tlvs, expected) {
+		t.Errorf("Expected TLVs %+v, got %+v", expected, tlvs)
+	}
+}
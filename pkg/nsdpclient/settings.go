@@ -0,0 +1,147 @@
+package nsdpclient
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+// DHCP modes of the 0x000b parameter
+const (
+	DHCPDisabled uint8 = 0x00
+	DHCPEnabled  uint8 = 0x01
+)
+
+// DeviceSettings holds identification and IP settings to change. Nil fields
+// are left unchanged.
+type DeviceSettings struct {
+	Name     *string
+	Location *string
+
+	IP       net.IP
+	Netmask  net.IP
+	Gateway  net.IP
+	DHCPMode *uint8
+}
+
+// IsEmpty reports whether no setting is to be changed.
+func (s DeviceSettings) IsEmpty() bool {
+	return s.Name == nil && s.Location == nil && s.IP == nil && s.Netmask == nil && s.Gateway == nil && s.DHCPMode == nil
+}
+
+// Validate checks the settings against the current configuration of the
+// device. The resulting IP configuration must be consistent, since a wrong
+// address or gateway cuts off management access via IP.
+func (s DeviceSettings) Validate(current *DeviceInfo) error {
+	for _, field := range []struct {
+		name string
+		ip   net.IP
+	}{{"IP address", s.IP}, {"netmask", s.Netmask}, {"gateway", s.Gateway}} {
+		if field.ip != nil && field.ip.To4() == nil {
+			return fmt.Errorf("%s %s is not an IPv4 address", field.name, field.ip)
+		}
+	}
+	if s.DHCPMode != nil && *s.DHCPMode != DHCPDisabled && *s.DHCPMode != DHCPEnabled {
+		return fmt.Errorf("invalid DHCP mode %d", *s.DHCPMode)
+	}
+	if s.IP == nil && s.Netmask == nil && s.Gateway == nil {
+		return nil
+	}
+	// Static settings only take effect with DHCP disabled afterwards
+	dhcpMode := current.DHCPMode
+	if s.DHCPMode != nil {
+		dhcpMode = s.DHCPMode
+	}
+	if dhcpMode != nil && *dhcpMode == DHCPEnabled {
+		if s.DHCPMode != nil {
+			return fmt.Errorf("static IP settings have no effect with DHCP enabled")
+		}
+		return fmt.Errorf("static IP settings have no effect while DHCP is enabled; disable DHCP in the same change")
+	}
+
+	// Check the resulting static configuration as a whole
+	ip, netmask, gateway := current.IP, current.Netmask, current.Gateway
+	if s.IP != nil {
+		ip = s.IP
+	}
+	if s.Netmask != nil {
+		netmask = s.Netmask
+	}
+	if s.Gateway != nil {
+		gateway = s.Gateway
+	}
+	if ip == nil || netmask == nil {
+		return fmt.Errorf("IP address and netmask are both required")
+	}
+	mask := net.IPMask(netmask.To4())
+	if ones, bits := mask.Size(); bits == 0 || ones == 0 {
+		return fmt.Errorf("netmask %s is not a valid network mask", netmask)
+	}
+	network := &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+	if ip.Equal(network.IP) || ip.Equal(directedBroadcast(network)) {
+		return fmt.Errorf("IP address %s is the network or broadcast address of %s", ip, network)
+	}
+	if gateway != nil && !gateway.IsUnspecified() && !network.Contains(gateway) {
+		return fmt.Errorf("gateway %s is outside of network %s", gateway, network)
+	}
+	return nil
+}
+
+// Mismatches returns the settings the device does not report as expected,
+// for verifying a change.
+func (s DeviceSettings) Mismatches(info *DeviceInfo) []string {
+	var mismatches []string
+	if s.Name != nil && info.Name != *s.Name {
+		mismatches = append(mismatches, fmt.Sprintf("name is %q instead of %q", info.Name, *s.Name))
+	}
+	if s.Location != nil && info.Location != *s.Location {
+		mismatches = append(mismatches, fmt.Sprintf("location is %q instead of %q", info.Location, *s.Location))
+	}
+	if s.IP != nil && !s.IP.Equal(info.IP) {
+		mismatches = append(mismatches, fmt.Sprintf("IP address is %s instead of %s", info.IP, s.IP))
+	}
+	if s.Netmask != nil && !s.Netmask.Equal(info.Netmask) {
+		mismatches = append(mismatches, fmt.Sprintf("netmask is %s instead of %s", info.Netmask, s.Netmask))
+	}
+	if s.Gateway != nil && !s.Gateway.Equal(info.Gateway) {
+		mismatches = append(mismatches, fmt.Sprintf("gateway is %s instead of %s", info.Gateway, s.Gateway))
+	}
+	if s.DHCPMode != nil && (info.DHCPMode == nil || *info.DHCPMode != *s.DHCPMode) {
+		mismatches = append(mismatches, "DHCP mode was not changed")
+	}
+	return mismatches
+}
+
+func (s DeviceSettings) tlvs() []nsdp.TLV {
+	var tlvs []nsdp.TLV
+	if s.Name != nil {
+		tlvs = append(tlvs, nsdp.NewDeviceName(*s.Name))
+	}
+	if s.Location != nil {
+		tlvs = append(tlvs, nsdp.NewDeviceLocation(*s.Location))
+	}
+	// Disable DHCP before setting static addresses
+	if s.DHCPMode != nil {
+		tlvs = append(tlvs, nsdp.NewDHCPMode(*s.DHCPMode))
+	}
+	if s.IP != nil {
+		tlvs = append(tlvs, nsdp.NewDeviceIP(s.IP.To4()))
+	}
+	if s.Netmask != nil {
+		tlvs = append(tlvs, nsdp.NewDeviceNetmask(s.Netmask.To4()))
+	}
+	if s.Gateway != nil {
+		tlvs = append(tlvs, nsdp.NewRouterIP(s.Gateway.To4()))
+	}
+	return tlvs
+}
+
+// ApplySettings writes the given settings to a device in a single write
+// request.
+func (c *Client) ApplySettings(mac net.HardwareAddr, password string, settings DeviceSettings) error {
+	if settings.IsEmpty() {
+		return fmt.Errorf("no settings to change")
+	}
+	return c.writeParams(mac, password, settings.tlvs())
+}
@@ -0,0 +1,77 @@
+package nsdpclient
+
+import (
+	"net"
+	"testing"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+func TestDeviceSettingsValidate(t *testing.T) {
+	current := &DeviceInfo{
+		IP:      net.IPv4(10, 1, 0, 3),
+		Netmask: net.IPv4(255, 255, 0, 0),
+		Gateway: net.IPv4(10, 1, 0, 1),
+	}
+	enabled := DHCPEnabled
+	disabled := DHCPDisabled
+	dhcpDevice := &DeviceInfo{IP: current.IP, Netmask: current.Netmask, Gateway: current.Gateway, DHCPMode: &enabled}
+
+	tests := []struct {
+		name     string
+		settings DeviceSettings
+		valid    bool
+	}{
+		{"new address in network", DeviceSettings{IP: net.IPv4(10, 1, 2, 3)}, true},
+		{"address outside of gateway network", DeviceSettings{IP: net.IPv4(10, 2, 0, 3)}, false},
+		{"new network", DeviceSettings{IP: net.IPv4(192, 168, 1, 10), Netmask: net.IPv4(255, 255, 255, 0), Gateway: net.IPv4(192, 168, 1, 1)}, true},
+		{"invalid netmask", DeviceSettings{Netmask: net.IPv4(255, 0, 255, 0)}, false},
+		{"broadcast address", DeviceSettings{IP: net.IPv4(10, 1, 255, 255)}, false},
+		{"IPv6 address", DeviceSettings{IP: net.ParseIP("fe80::1")}, false},
+		{"DHCP with static address", DeviceSettings{IP: net.IPv4(10, 1, 2, 3), DHCPMode: &enabled}, false},
+		{"DHCP only", DeviceSettings{DHCPMode: &enabled}, true},
+	}
+	for _, tt := range tests {
+		err := tt.settings.Validate(current)
+		if tt.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	// Static settings of a device using DHCP need DHCP disabled with them
+	if err := (DeviceSettings{IP: net.IPv4(10, 1, 2, 3)}).Validate(dhcpDevice); err == nil {
+		t.Error("Expected an error for a static address while DHCP stays enabled")
+	}
+	if err := (DeviceSettings{IP: net.IPv4(10, 1, 2, 3), DHCPMode: &disabled}).Validate(dhcpDevice); err != nil {
+		t.Errorf("Unexpected error for a static address with DHCP disabled: %v", err)
+	}
+}
+
+func TestDeviceSettingsMismatches(t *testing.T) {
+	name := "switch1"
+	location := "rack 2"
+	settings := DeviceSettings{Name: &name, Location: &location, IP: net.IPv4(10, 1, 0, 3)}
+	info := &DeviceInfo{Name: "switch1", Location: "rack 1", IP: net.IPv4(10, 1, 0, 3)}
+
+	mismatches := settings.Mismatches(info)
+	if len(mismatches) != 1 {
+		t.Errorf("Expected 1 mismatch, got %v", mismatches)
+	}
+}
+
+func TestApplySettings(t *testing.T) {
+	client := newTestClient(t, testResponse(nsdp.WriteResponse, 0))
+	client.SetPasswordScheme(PasswordXOR)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	name := "switch2"
+	if err := client.ApplySettings(mac, "password", DeviceSettings{Name: &name}); err != nil {
+		t.Errorf("ApplySettings failed: %v", err)
+	}
+	if err := client.ApplySettings(mac, "password", DeviceSettings{}); err == nil {
+		t.Error("Expected error for empty settings")
+	}
+}
@@ -0,0 +1,89 @@
+package nsdpclient
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+// Length of a port statistics (0x1000) record
+const portStatisticLen = 49
+
+// DecodePortStatistic decodes a raw port statistics record (0x1000) into the
+// same type the library uses for it. The record holds the port number
+// followed by six 64 bit counters: received bytes, sent bytes, packets,
+// broadcasts, multicasts and errors.
+func DecodePortStatistic(value []byte) (*nsdp.PortStatistic, error) {
+	if len(value) != portStatisticLen {
+		return nil, fmt.Errorf("unexpected port statistics length: %d", len(value))
+	}
+	counter := func(i int) uint64 {
+		return binary.BigEndian.Uint64(value[1+i*8 : 9+i*8])
+	}
+	return nsdp.NewPortStatistic(value[0], counter(0), counter(1), counter(2), counter(3), counter(4), counter(5)), nil
+}
+
+// PortRates holds the traffic of a port between two statistics samples.
+type PortRates struct {
+	Port    uint8
+	RXBytes float64 // Received bytes per second
+	TXBytes float64 // Sent bytes per second
+	Packets float64 // Packets per second
+	Errors  uint64  // Errors since the previous sample
+	Reset   bool    // Counters were reset; rates only cover the time since then
+}
+
+// CounterDelta returns the increase of a counter between two samples. A
+// counter that went back is taken as wrapped if it was in the upper quarter
+// of the 32 or 64 bit range and is now in the lower one; otherwise the
+// counters were reset, by a reboot or reset-stats, and the new value is the
+// increase.
+func CounterDelta(previous, current uint64) (delta uint64, reset bool) {
+	if current >= previous {
+		return current - previous, false
+	}
+	for _, limit := range []uint64{math.MaxUint32, math.MaxUint64} {
+		if previous <= limit && previous > limit-limit/4 && current <= limit/4 {
+			return limit - previous + current + 1, false
+		}
+	}
+	return current, true
+}
+
+// ComputePortRates computes the rates of each port present in both samples,
+// taken elapsed apart.
+func ComputePortRates(previous, current []*nsdp.PortStatistic, elapsed time.Duration) []PortRates {
+	seconds := elapsed.Seconds()
+	if seconds <= 0 {
+		return nil
+	}
+	var rates []PortRates
+	for _, cur := range current {
+		var prev *nsdp.PortStatistic
+		for _, p := range previous {
+			if p.Port == cur.Port {
+				prev = p
+			}
+		}
+		if prev == nil {
+			continue
+		}
+
+		r := PortRates{Port: cur.Port}
+		var reset [4]bool
+		var rx, tx, packets uint64
+		rx, reset[0] = CounterDelta(prev.Received, cur.Received)
+		tx, reset[1] = CounterDelta(prev.Sent, cur.Sent)
+		packets, reset[2] = CounterDelta(prev.Packets, cur.Packets)
+		r.Errors, reset[3] = CounterDelta(prev.Errors, cur.Errors)
+		r.RXBytes = float64(rx) / seconds
+		r.TXBytes = float64(tx) / seconds
+		r.Packets = float64(packets) / seconds
+		r.Reset = reset[0] || reset[1] || reset[2] || reset[3]
+		rates = append(rates, r)
+	}
+	return rates
+}
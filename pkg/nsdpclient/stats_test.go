@@ -0,0 +1,85 @@
+package nsdpclient
+
+import (
+	"encoding/hex"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+func TestDecodePortStatistic(t *testing.T) {
+	// Port 1 record of the GS108Ev3 test response
+	value, _ := hex.DecodeString("01000000011b86e2c2000000000d159e380000000000000000000000000000000000000000000000000000000000000000")
+	stat, err := DecodePortStatistic(value)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	expected := nsdp.NewPortStatistic(1, 0x011b86e2c2, 0x0d159e38, 0, 0, 0, 0)
+	if !reflect.DeepEqual(stat, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, stat)
+	}
+
+	value = make([]byte, portStatisticLen)
+	value[0] = 2
+	for i := 0; i < 6; i++ {
+		value[8+i*8] = byte(i + 1)
+	}
+	stat, err = DecodePortStatistic(value)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	expected = nsdp.NewPortStatistic(2, 1, 2, 3, 4, 5, 6)
+	if !reflect.DeepEqual(stat, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, stat)
+	}
+
+	if _, err := DecodePortStatistic(value[:25]); err == nil {
+		t.Error("Expected error for short record")
+	}
+}
+
+func TestCounterDelta(t *testing.T) {
+	for _, test := range []struct {
+		previous, current uint64
+		delta             uint64
+		reset             bool
+	}{
+		{100, 250, 150, false},
+		{100, 100, 0, false},
+		{math.MaxUint32 - 9, 5, 15, false},
+		{math.MaxUint64 - 9, 5, 15, false},
+		{5000000, 1200, 1200, true},
+		{math.MaxUint32 - 9, math.MaxUint32 / 2, math.MaxUint32 / 2, true},
+	} {
+		delta, reset := CounterDelta(test.previous, test.current)
+		if delta != test.delta || reset != test.reset {
+			t.Errorf("%d -> %d: expected %d (reset %v), got %d (reset %v)", test.previous, test.current, test.delta, test.reset, delta, reset)
+		}
+	}
+}
+
+func TestComputePortRates(t *testing.T) {
+	previous := []*nsdp.PortStatistic{
+		nsdp.NewPortStatistic(1, 1000, 2000, 30, 0, 0, 1),
+		nsdp.NewPortStatistic(2, 5000, 5000, 50, 0, 0, 0),
+	}
+	current := []*nsdp.PortStatistic{
+		nsdp.NewPortStatistic(1, 6000, 2000, 80, 0, 0, 3),
+		nsdp.NewPortStatistic(2, 100, 0, 1, 0, 0, 0),
+		nsdp.NewPortStatistic(3, 100, 100, 1, 0, 0, 0),
+	}
+	rates := ComputePortRates(previous, current, 5*time.Second)
+	expected := []PortRates{
+		{Port: 1, RXBytes: 1000, TXBytes: 0, Packets: 10, Errors: 2},
+		{Port: 2, RXBytes: 20, TXBytes: 0, Packets: 0.2, Reset: true},
+	}
+	if !reflect.DeepEqual(rates, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rates)
+	}
+	if rates := ComputePortRates(previous, current, 0); rates != nil {
+		t.Errorf("Expected no rates without elapsed time, got %+v", rates)
+	}
+}
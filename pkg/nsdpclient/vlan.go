@@ -0,0 +1,306 @@
+package nsdpclient
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+)
+
+// VLANMembership describes the member ports of one 802.1Q VLAN as reported
+// by the 0x2800 parameter.
+type VLANMembership struct {
+	ID      uint16
+	Members []uint8 // All member ports, ascending
+	Tagged  []uint8 // Member ports sending tagged frames, ascending
+}
+
+// Untagged returns the member ports sending untagged frames.
+func (m VLANMembership) Untagged() []uint8 {
+	var untagged []uint8
+	for _, port := range m.Members {
+		if !containsPort(m.Tagged, port) {
+			untagged = append(untagged, port)
+		}
+	}
+	return untagged
+}
+
+// IsMember reports whether port is a (tagged or untagged) member of the VLAN.
+func (m VLANMembership) IsMember(port uint8) bool {
+	return containsPort(m.Members, port)
+}
+
+// IsTagged reports whether port is a tagged member of the VLAN.
+func (m VLANMembership) IsTagged(port uint8) bool {
+	return containsPort(m.Tagged, port)
+}
+
+// DecodeVLAN8021Q decodes the records of the 802.1Q VLAN membership parameter
+// (0x2800). Each record holds the VLAN ID followed by two port bitmaps of
+// equal width, the first listing the member ports and the second the tagged
+// ones. The bitmap width grows with the port count: one byte covers switches
+// with up to 8 ports, six bytes cover 48 ports.
+func DecodeVLAN8021Q(records [][]byte) ([]VLANMembership, error) {
+	memberships := make([]VLANMembership, 0, len(records))
+	for _, record := range records {
+		if len(record) < 4 || len(record)%2 != 0 {
+			return nil, fmt.Errorf("unexpected 802.1Q VLAN record length: %d", len(record))
+		}
+		width := (len(record) - 2) / 2
+		memberships = append(memberships, VLANMembership{
+			ID:      binary.BigEndian.Uint16(record[0:2]),
+			Members: decodePortBitmap(record[2 : 2+width]),
+			Tagged:  decodePortBitmap(record[2+width:]),
+		})
+	}
+	return memberships, nil
+}
+
+// PortPVID is the default VLAN (PVID) of a port as reported by the 0x3000
+// parameter.
+type PortPVID struct {
+	Port uint8
+	VLAN uint16
+}
+
+// DecodeVLANPVID decodes the records of the 802.1Q PVID parameter (0x3000).
+// Each record holds the port number followed by its 16 bit VLAN ID.
+func DecodeVLANPVID(records [][]byte) ([]PortPVID, error) {
+	pvids := make([]PortPVID, 0, len(records))
+	for _, record := range records {
+		if len(record) != 3 {
+			return nil, fmt.Errorf("unexpected PVID record length: %d", len(record))
+		}
+		pvids = append(pvids, PortPVID{
+			Port: record[0],
+			VLAN: binary.BigEndian.Uint16(record[1:3]),
+		})
+	}
+	return pvids, nil
+}
+
+// PVIDIssue describes a port whose PVID does not match the VLAN membership.
+type PVIDIssue struct {
+	Port    uint8
+	PVID    uint16
+	Problem string
+}
+
+// CheckPVIDs returns the ports whose PVID is not an untagged member of that
+// VLAN. Frames arriving untagged on such a port are assigned to a VLAN the
+// port does not send untagged traffic for, which is the most common 802.1Q
+// misconfiguration.
+func CheckPVIDs(pvids []PortPVID, memberships []VLANMembership) []PVIDIssue {
+	var issues []PVIDIssue
+	for _, pvid := range pvids {
+		problem := ""
+		membership := findVLAN(memberships, pvid.VLAN)
+		switch {
+		case membership == nil:
+			problem = fmt.Sprintf("VLAN %d does not exist", pvid.VLAN)
+		case !membership.IsMember(pvid.Port):
+			problem = fmt.Sprintf("port is not a member of VLAN %d", pvid.VLAN)
+		case membership.IsTagged(pvid.Port):
+			problem = fmt.Sprintf("port is a tagged member of VLAN %d", pvid.VLAN)
+		}
+		if problem != "" {
+			issues = append(issues, PVIDIssue{Port: pvid.Port, PVID: pvid.VLAN, Problem: problem})
+		}
+	}
+	return issues
+}
+
+func findVLAN(memberships []VLANMembership, id uint16) *VLANMembership {
+	for i := range memberships {
+		if memberships[i].ID == id {
+			return &memberships[i]
+		}
+	}
+	return nil
+}
+
+// decodePortBitmap returns the ports set in an NSDP port bitmap. The most
+// significant bit of the first byte represents port 1.
+func decodePortBitmap(bitmap []byte) []uint8 {
+	var ports []uint8
+	for i, b := range bitmap {
+		for bit := 0; bit < 8; bit++ {
+			if b&(0x80>>bit) != 0 {
+				ports = append(ports, uint8(i*8+bit+1))
+			}
+		}
+	}
+	return ports
+}
+
+// sortedPorts returns the ports in ascending order without duplicates.
+func sortedPorts(ports []uint8) []uint8 {
+	var sorted []uint8
+	for _, port := range ports {
+		if !containsPort(sorted, port) {
+			sorted = append(sorted, port)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+func containsPort(ports []uint8, port uint8) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+func equalPorts(a, b []uint8) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// VLAN engine modes of the 0x2000 parameter
+const (
+	VLANEngineDisabled          uint8 = 0x00
+	VLANEngineBasicPortBased    uint8 = 0x01
+	VLANEngineAdvancedPortBased uint8 = 0x02
+	VLANEngineBasic8021Q        uint8 = 0x03
+	VLANEngineAdvanced8021Q     uint8 = 0x04
+)
+
+// VLANConfig is the VLAN configuration of a device.
+type VLANConfig struct {
+	Engine      *uint8 // nil if not reported
+	Memberships []VLANMembership
+	PVIDs       []PortPVID
+	BitmapWidth int // Width of the port bitmaps in bytes (0: unknown)
+}
+
+// Membership returns the membership of a VLAN, or nil if it does not exist.
+func (c *VLANConfig) Membership(id uint16) *VLANMembership {
+	return findVLAN(c.Memberships, id)
+}
+
+// PVID returns the PVID of a port, or 0 if it is unknown.
+func (c *VLANConfig) PVID(port uint8) uint16 {
+	for _, pvid := range c.PVIDs {
+		if pvid.Port == port {
+			return pvid.VLAN
+		}
+	}
+	return 0
+}
+
+// VLANConfig reads the VLAN engine, the 802.1Q memberships and the PVIDs of
+// a device in one request.
+func (c *Client) VLANConfig(mac net.HardwareAddr) (*VLANConfig, error) {
+	params, err := c.ReadParams(mac, ParamVLANEngine, ParamVLAN8021Q, ParamVLANPVID)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &VLANConfig{}
+	if engine := params.Get(ParamVLANEngine); len(engine) >= 1 {
+		config.Engine = &engine[0]
+	}
+	if config.Memberships, err = DecodeVLAN8021Q(params.Records(ParamVLAN8021Q)); err != nil {
+		return nil, err
+	}
+	if record := params.Get(ParamVLAN8021Q); record != nil {
+		config.BitmapWidth = (len(record) - 2) / 2
+	}
+	if config.PVIDs, err = DecodeVLANPVID(params.Records(ParamVLANPVID)); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// PortBitmapWidth returns the port bitmap width in bytes for a port count.
+func PortBitmapWidth(portCount int) int {
+	return (portCount + 7) / 8
+}
+
+// EncodeVLAN8021Q encodes a VLAN membership as a 0x2800 record with port
+// bitmaps of the given width.
+func EncodeVLAN8021Q(m VLANMembership, width int) ([]byte, error) {
+	record := make([]byte, 2, 2+2*width)
+	binary.BigEndian.PutUint16(record, m.ID)
+	members, err := encodePortBitmap(m.Members, width)
+	if err != nil {
+		return nil, err
+	}
+	tagged, err := encodePortBitmap(m.Tagged, width)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(record, members...), tagged...), nil
+}
+
+// encodePortBitmap is the inverse of decodePortBitmap.
+func encodePortBitmap(ports []uint8, width int) ([]byte, error) {
+	bitmap := make([]byte, width)
+	for _, port := range ports {
+		if port == 0 || int(port) > width*8 {
+			return nil, fmt.Errorf("port %d out of range", port)
+		}
+		bitmap[(port-1)/8] |= 0x80 >> ((port - 1) % 8)
+	}
+	return bitmap, nil
+}
+
+// SetVLANMembership creates an 802.1Q VLAN or replaces its member ports.
+func (c *Client) SetVLANMembership(mac net.HardwareAddr, password string, m VLANMembership, width int) error {
+	record, err := EncodeVLAN8021Q(m, width)
+	if err != nil {
+		return err
+	}
+	return c.WriteParams(mac, password, ParamValue{Param: ParamVLAN8021Q, Value: record})
+}
+
+// DeleteVLAN deletes an 802.1Q VLAN.
+func (c *Client) DeleteVLAN(mac net.HardwareAddr, password string, id uint16) error {
+	value := make([]byte, 2)
+	binary.BigEndian.PutUint16(value, id)
+	return c.WriteParams(mac, password, ParamValue{Param: ParamVLANDelete, Value: value})
+}
+
+// SetPVID sets the default VLAN of a port.
+func (c *Client) SetPVID(mac net.HardwareAddr, password string, port uint8, vlan uint16) error {
+	value := []byte{port, 0, 0}
+	binary.BigEndian.PutUint16(value[1:], vlan)
+	return c.WriteParams(mac, password, ParamValue{Param: ParamVLANPVID, Value: value})
+}
+
+// SetVLANEngine switches the VLAN engine mode. Switching the mode resets the
+// VLAN configuration on most models.
+func (c *Client) SetVLANEngine(mac net.HardwareAddr, password string, mode uint8) error {
+	return c.WriteParams(mac, password, ParamValue{Param: ParamVLANEngine, Value: []byte{mode}})
+}
+
+// CheckManagementAccess checks that management traffic arriving untagged on
+// the uplink port still reaches the management VLAN with the given
+// configuration: the uplink must be a member of the management VLAN and, if
+// untagged, have it as PVID.
+func CheckManagementAccess(config *VLANConfig, mgmtVLAN uint16, uplink uint8) error {
+	membership := config.Membership(mgmtVLAN)
+	switch {
+	case membership == nil:
+		return fmt.Errorf("management VLAN %d would not exist", mgmtVLAN)
+	case !membership.IsMember(uplink):
+		return fmt.Errorf("uplink port %d would not be a member of management VLAN %d", uplink, mgmtVLAN)
+	case membership.IsTagged(uplink):
+		return nil
+	}
+	if pvid := config.PVID(uplink); pvid != 0 && pvid != mgmtVLAN {
+		return fmt.Errorf("uplink port %d would have PVID %d instead of management VLAN %d", uplink, pvid, mgmtVLAN)
+	}
+	return nil
+}
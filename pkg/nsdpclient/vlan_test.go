@@ -0,0 +1,186 @@
+package nsdpclient
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+func TestDecodeVLAN8021Q(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   []byte
+		id       uint16
+		members  []uint8
+		tagged   []uint8
+		untagged []uint8
+	}{
+		{
+			name:     "5 ports",
+			record:   []byte{0x00, 0x01, 0xf8, 0x08},
+			id:       1,
+			members:  []uint8{1, 2, 3, 4, 5},
+			tagged:   []uint8{5},
+			untagged: []uint8{1, 2, 3, 4},
+		},
+		{
+			name:     "8 ports",
+			record:   []byte{0x00, 0x0a, 0x03, 0x01},
+			id:       10,
+			members:  []uint8{7, 8},
+			tagged:   []uint8{8},
+			untagged: []uint8{7},
+		},
+		{
+			name: "48 ports",
+			record: []byte{0x0f, 0xa0,
+				0x80, 0x00, 0x00, 0x00, 0x00, 0x03,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+			id:       4000,
+			members:  []uint8{1, 47, 48},
+			tagged:   []uint8{48},
+			untagged: []uint8{1, 47},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberships, err := DecodeVLAN8021Q([][]byte{tt.record})
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			m := memberships[0]
+			if m.ID != tt.id {
+				t.Errorf("Expected VLAN %d, got %d", tt.id, m.ID)
+			}
+			if !reflect.DeepEqual(m.Members, tt.members) {
+				t.Errorf("Expected members %v, got %v", tt.members, m.Members)
+			}
+			if !reflect.DeepEqual(m.Tagged, tt.tagged) {
+				t.Errorf("Expected tagged %v, got %v", tt.tagged, m.Tagged)
+			}
+			if !reflect.DeepEqual(m.Untagged(), tt.untagged) {
+				t.Errorf("Expected untagged %v, got %v", tt.untagged, m.Untagged())
+			}
+		})
+	}
+}
+
+func TestDecodeVLAN8021QInvalid(t *testing.T) {
+	for _, record := range [][]byte{{}, {0x00, 0x01}, {0x00, 0x01, 0xff}} {
+		if _, err := DecodeVLAN8021Q([][]byte{record}); err == nil {
+			t.Errorf("Expected error for record %x", record)
+		}
+	}
+}
+
+func TestDecodeVLANPVID(t *testing.T) {
+	pvids, err := DecodeVLANPVID([][]byte{{0x01, 0x00, 0x01}, {0x08, 0x00, 0x0a}})
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	expected := []PortPVID{{Port: 1, VLAN: 1}, {Port: 8, VLAN: 10}}
+	if !reflect.DeepEqual(pvids, expected) {
+		t.Errorf("Expected %v, got %v", expected, pvids)
+	}
+
+	if _, err := DecodeVLANPVID([][]byte{{0x01, 0x00}}); err == nil {
+		t.Error("Expected error for short record")
+	}
+}
+
+func TestCheckPVIDs(t *testing.T) {
+	memberships := []VLANMembership{
+		{ID: 1, Members: []uint8{1, 2, 3, 4}, Tagged: []uint8{4}},
+		{ID: 10, Members: []uint8{3, 4}, Tagged: []uint8{4}},
+	}
+	pvids := []PortPVID{
+		{Port: 1, VLAN: 1},  // untagged member: fine
+		{Port: 2, VLAN: 10}, // not a member
+		{Port: 3, VLAN: 10}, // untagged member: fine
+		{Port: 4, VLAN: 1},  // tagged member
+		{Port: 5, VLAN: 20}, // VLAN does not exist
+	}
+
+	issues := CheckPVIDs(pvids, memberships)
+	var ports []uint8
+	for _, issue := range issues {
+		ports = append(ports, issue.Port)
+	}
+	if !reflect.DeepEqual(ports, []uint8{2, 4, 5}) {
+		t.Errorf("Expected issues for ports [2 4 5], got %v", issues)
+	}
+}
+
+func TestEncodeVLAN8021Q(t *testing.T) {
+	m := VLANMembership{ID: 4000, Members: []uint8{1, 47, 48}, Tagged: []uint8{48}}
+	record, err := EncodeVLAN8021Q(m, PortBitmapWidth(48))
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	decoded, err := DecodeVLAN8021Q([][]byte{record})
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded[0], m) {
+		t.Errorf("Expected %+v, got %+v", m, decoded[0])
+	}
+
+	if _, err := EncodeVLAN8021Q(VLANMembership{ID: 1, Members: []uint8{9}}, PortBitmapWidth(8)); err == nil {
+		t.Error("Expected error for port beyond the bitmap width")
+	}
+}
+
+func TestCheckManagementAccess(t *testing.T) {
+	config := &VLANConfig{
+		Memberships: []VLANMembership{
+			{ID: 1, Members: []uint8{1, 2, 8}, Tagged: []uint8{8}},
+			{ID: 10, Members: []uint8{3, 8}, Tagged: []uint8{8}},
+		},
+		PVIDs: []PortPVID{{Port: 1, VLAN: 1}, {Port: 2, VLAN: 10}, {Port: 3, VLAN: 10}, {Port: 8, VLAN: 1}},
+	}
+
+	tests := []struct {
+		uplink   uint8
+		mgmtVLAN uint16
+		ok       bool
+	}{
+		{uplink: 1, mgmtVLAN: 1, ok: true},   // untagged member with matching PVID
+		{uplink: 8, mgmtVLAN: 10, ok: true},  // tagged member
+		{uplink: 2, mgmtVLAN: 1, ok: false},  // PVID points elsewhere
+		{uplink: 3, mgmtVLAN: 1, ok: false},  // not a member
+		{uplink: 1, mgmtVLAN: 20, ok: false}, // VLAN does not exist
+	}
+	for _, tt := range tests {
+		err := CheckManagementAccess(config, tt.mgmtVLAN, tt.uplink)
+		if tt.ok != (err == nil) {
+			t.Errorf("Uplink %d, VLAN %d: unexpected result %v", tt.uplink, tt.mgmtVLAN, err)
+		}
+	}
+}
+
+func TestVLANConfig(t *testing.T) {
+	client := newTestClient(t, testResponse(nsdp.ReadResponse, 0,
+		&nsdp.GenericTLV{Type: ParamVLANEngine, Length: 1, Value: []byte{VLANEngineAdvanced8021Q}},
+		&nsdp.GenericTLV{Type: ParamVLAN8021Q, Length: 4, Value: []byte{0x00, 0x01, 0xff, 0x00}},
+		&nsdp.GenericTLV{Type: ParamVLAN8021Q, Length: 4, Value: []byte{0x00, 0x0a, 0x03, 0x01}},
+		&nsdp.GenericTLV{Type: ParamVLANPVID, Length: 3, Value: []byte{0x07, 0x00, 0x0a}},
+	))
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	config, err := client.VLANConfig(mac)
+	if err != nil {
+		t.Fatalf("VLANConfig failed: %v", err)
+	}
+	if config.Engine == nil || *config.Engine != VLANEngineAdvanced8021Q {
+		t.Errorf("Unexpected engine: %v", config.Engine)
+	}
+	if len(config.Memberships) != 2 || config.BitmapWidth != 1 {
+		t.Errorf("Unexpected memberships: %+v (width %d)", config.Memberships, config.BitmapWidth)
+	}
+	if config.PVID(7) != 10 || config.PVID(1) != 0 {
+		t.Errorf("Unexpected PVIDs: %+v", config.PVIDs)
+	}
+}
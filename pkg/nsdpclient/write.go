@@ -0,0 +1,243 @@
+package nsdpclient
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+// Parameters used for authenticating write requests
+const (
+	ParamPassword           = 0x000a // Admin password (plain or XOR scrambled)
+	ParamPasswordEncryption = 0x0014 // Password scheme expected by the device
+	ParamPasswordSalt       = 0x0017 // Salt for hashed passwords
+	ParamPasswordHash       = 0x001a // Hashed admin password
+)
+
+// PasswordScheme selects how the admin password is sent with write requests.
+type PasswordScheme int
+
+const (
+	// PasswordAuto asks the device for its scheme before each write.
+	PasswordAuto PasswordScheme = iota
+	// PasswordPlain sends the password in clear text.
+	PasswordPlain
+	// PasswordXOR scrambles the password with a fixed key (legacy firmware).
+	PasswordXOR
+	// PasswordHashed sends a hash over the device MAC, the password and a
+	// salt provided by the device (recent firmware).
+	PasswordHashed
+)
+
+func (s PasswordScheme) String() string {
+	switch s {
+	case PasswordAuto:
+		return "auto"
+	case PasswordPlain:
+		return "plain"
+	case PasswordXOR:
+		return "xor"
+	case PasswordHashed:
+		return "hashed"
+	}
+	return fmt.Sprintf("PasswordScheme(%d)", int(s))
+}
+
+// ParsePasswordScheme parses the name of a password scheme as returned by
+// PasswordScheme.String.
+func ParsePasswordScheme(name string) (PasswordScheme, error) {
+	for _, scheme := range []PasswordScheme{PasswordAuto, PasswordPlain, PasswordXOR, PasswordHashed} {
+		if scheme.String() == name {
+			return scheme, nil
+		}
+	}
+	return PasswordAuto, fmt.Errorf("unknown password scheme %q (supported: auto, plain, xor, hashed)", name)
+}
+
+// Key the legacy firmware scrambles passwords with
+const passwordXORKey = "NtgrSmartSwitchRock"
+
+// scramblePassword applies the legacy XOR scrambling to a password.
+func scramblePassword(password string) []byte {
+	scrambled := []byte(password)
+	for i := range scrambled {
+		scrambled[i] ^= passwordXORKey[i%len(passwordXORKey)]
+	}
+	return scrambled
+}
+
+// hashPassword returns the MD5 hash over the device MAC, the password and
+// the salt the device reported.
+func hashPassword(mac net.HardwareAddr, password string, salt []byte) []byte {
+	hash := md5.New()
+	hash.Write(mac)
+	hash.Write([]byte(password))
+	hash.Write(salt)
+	return hash.Sum(nil)
+}
+
+// WriteErrorCode is the reason a device gives for rejecting a write request.
+type WriteErrorCode uint8
+
+// Known write error codes
+const (
+	WriteErrProtocolVersion WriteErrorCode = 0x01
+	WriteErrCommand         WriteErrorCode = 0x02
+	WriteErrUnsupportedTLV  WriteErrorCode = 0x03
+	WriteErrTLVLength       WriteErrorCode = 0x04
+	WriteErrInvalidValue    WriteErrorCode = 0x05
+	WriteErrBlockedByACL    WriteErrorCode = 0x06
+	WriteErrBadPassword     WriteErrorCode = 0x07
+	WriteErrFirmwareUpdate  WriteErrorCode = 0x08
+	WriteErrBadUsername     WriteErrorCode = 0x09
+	WriteErrManagerOnly     WriteErrorCode = 0x0a
+	WriteErrNotAllowed      WriteErrorCode = 0x0b
+	WriteErrLockedOut       WriteErrorCode = 0x0c
+)
+
+func (c WriteErrorCode) String() string {
+	switch c {
+	case WriteErrProtocolVersion:
+		return "protocol version not supported"
+	case WriteErrCommand:
+		return "command not supported"
+	case WriteErrUnsupportedTLV:
+		return "parameter not supported"
+	case WriteErrTLVLength:
+		return "invalid value length"
+	case WriteErrInvalidValue:
+		return "invalid value"
+	case WriteErrBlockedByACL:
+		return "management access blocked by ACL"
+	case WriteErrBadPassword:
+		return "bad password"
+	case WriteErrFirmwareUpdate:
+		return "firmware update in progress"
+	case WriteErrBadUsername:
+		return "bad username"
+	case WriteErrManagerOnly:
+		return "configuration only allowed via the management interface"
+	case WriteErrNotAllowed:
+		return "command not allowed"
+	case WriteErrLockedOut:
+		return "locked out after too many failed logins"
+	}
+	return fmt.Sprintf("error 0x%02x", uint8(c))
+}
+
+// WriteError is returned if a device rejects a write request.
+type WriteError struct {
+	Device net.HardwareAddr
+	Code   WriteErrorCode
+	Param  uint16 // Parameter the device rejected (0 if not reported)
+}
+
+func (e *WriteError) Error() string {
+	if e.Param != 0 {
+		return fmt.Sprintf("device %s rejected %s: %s", e.Device, ParamDescription(e.Param), e.Code)
+	}
+	return fmt.Sprintf("device %s rejected write request: %s", e.Device, e.Code)
+}
+
+// writeResult checks the header of a write response. The device reports
+// the error code in the first byte of the result field and the rejected
+// parameter in the first two bytes following it.
+func writeResult(mac net.HardwareAddr, header *nsdp.Header) error {
+	if header.Result == 0 {
+		return nil
+	}
+	return &WriteError{
+		Device: mac,
+		Code:   WriteErrorCode(header.Result >> 8),
+		Param:  uint16(header.Unknown1 >> 16),
+	}
+}
+
+// ParamValue is a raw parameter value to write.
+type ParamValue struct {
+	Param uint16
+	Value []byte
+}
+
+// SetPasswordScheme selects how the admin password is sent with write
+// requests. The default is PasswordAuto.
+func (c *Client) SetPasswordScheme(scheme PasswordScheme) {
+	c.passwordScheme = scheme
+}
+
+// WriteParams writes raw parameter values to a single device, authenticated
+// with the admin password. A rejected request yields a *WriteError.
+func (c *Client) WriteParams(mac net.HardwareAddr, password string, values ...ParamValue) error {
+	tlvs := make([]nsdp.TLV, 0, len(values))
+	for _, value := range values {
+		tlvs = append(tlvs, &nsdp.GenericTLV{
+			Type:   value.Param,
+			Length: uint16(len(value.Value)),
+			Value:  value.Value,
+		})
+	}
+	return c.writeParams(mac, password, tlvs)
+}
+
+// writeParams sends a write request for the given TLVs to a single device,
+// preceded by the password TLV.
+func (c *Client) writeParams(mac net.HardwareAddr, password string, tlvs []nsdp.TLV) error {
+	passwordTLV, err := c.passwordTLV(mac, password)
+	if err != nil {
+		return err
+	}
+
+	responseMsg, err := c.sendRequest(nsdp.WriteRequest, mac, append([]nsdp.TLV{passwordTLV}, tlvs...))
+	if err != nil {
+		return err
+	}
+	return writeResult(mac, responseMsg.Header)
+}
+
+// passwordTLV returns the password TLV in the scheme the client is set to,
+// or the one the device asks for.
+func (c *Client) passwordTLV(mac net.HardwareAddr, password string) (nsdp.TLV, error) {
+	scheme := c.passwordScheme
+	var salt []byte
+	if scheme == PasswordAuto || scheme == PasswordHashed {
+		params, err := c.ReadParams(mac, ParamPasswordEncryption, ParamPasswordSalt)
+		if err != nil {
+			return nil, err
+		}
+		salt = params.Get(ParamPasswordSalt)
+		if scheme == PasswordAuto {
+			scheme = passwordSchemeOf(params.Get(ParamPasswordEncryption))
+			c.logf("Device %s uses %s passwords", mac, scheme)
+		}
+	}
+
+	switch scheme {
+	case PasswordPlain:
+		return &nsdp.GenericTLV{Type: ParamPassword, Length: uint16(len(password)), Value: []byte(password)}, nil
+	case PasswordHashed:
+		if len(salt) == 0 {
+			return nil, fmt.Errorf("device %s did not report a password salt", mac)
+		}
+		hash := hashPassword(mac, password, salt)
+		return &nsdp.GenericTLV{Type: ParamPasswordHash, Length: uint16(len(hash)), Value: hash}, nil
+	}
+	scrambled := scramblePassword(password)
+	return &nsdp.GenericTLV{Type: ParamPassword, Length: uint16(len(scrambled)), Value: scrambled}, nil
+}
+
+// passwordSchemeOf maps the password encryption parameter (0x0014) to a
+// scheme. Devices not reporting it expect the legacy XOR scrambling.
+func passwordSchemeOf(value []byte) PasswordScheme {
+	if len(value) == 0 {
+		return PasswordXOR
+	}
+	switch value[len(value)-1] {
+	case 0x00:
+		return PasswordPlain
+	case 0x01:
+		return PasswordXOR
+	}
+	return PasswordHashed
+}
@@ -0,0 +1,135 @@
+package nsdpclient
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hdecarne-github/go-nsdp"
+)
+
+func TestScramblePassword(t *testing.T) {
+	// The default password "password" as sent by legacy firmware
+	expected, _ := hex.DecodeString("3e15140124021316")
+	if got := scramblePassword("password"); !bytes.Equal(got, expected) {
+		t.Errorf("Expected %x, got %x", expected, got)
+	}
+}
+
+func TestPasswordSchemeOf(t *testing.T) {
+	tests := []struct {
+		value  []byte
+		scheme PasswordScheme
+	}{
+		{nil, PasswordXOR},
+		{[]byte{0x00}, PasswordPlain},
+		{[]byte{0x00, 0x01}, PasswordXOR},
+		{[]byte{0x08}, PasswordHashed},
+	}
+	for _, tt := range tests {
+		if got := passwordSchemeOf(tt.value); got != tt.scheme {
+			t.Errorf("%x: expected %s, got %s", tt.value, tt.scheme, got)
+		}
+	}
+}
+
+func TestParsePasswordScheme(t *testing.T) {
+	if scheme, err := ParsePasswordScheme("hashed"); err != nil || scheme != PasswordHashed {
+		t.Errorf("Unexpected result: %s, %v", scheme, err)
+	}
+	if _, err := ParsePasswordScheme("rot13"); err == nil {
+		t.Error("Expected error for unknown scheme")
+	}
+}
+
+func TestWriteParamsHashed(t *testing.T) {
+	client, requests := newRecordingClient(t,
+		testResponse(nsdp.ReadResponse, 0,
+			&nsdp.GenericTLV{Type: ParamPasswordEncryption, Length: 1, Value: []byte{0x08}},
+			&nsdp.GenericTLV{Type: ParamPasswordSalt, Length: 4, Value: []byte{0x01, 0x02, 0x03, 0x04}},
+		),
+		testResponse(nsdp.WriteResponse, 0),
+	)
+	client.SetPasswordScheme(PasswordHashed)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	if err := client.WriteParams(mac, "password", ParamValue{Param: ParamLoopDetection, Value: []byte{0x01}}); err != nil {
+		t.Fatalf("WriteParams failed: %v", err)
+	}
+
+	if operation, _ := nextRequest(t, requests); operation != nsdp.ReadRequest {
+		t.Errorf("Expected the salt to be read first, got operation %d", operation)
+	}
+	hash := md5.Sum(append(append([]byte(mac), "password"...), 0x01, 0x02, 0x03, 0x04))
+	assertWriteRequest(t, requests, ParamValue{Param: ParamPasswordHash, Value: hash[:]})
+}
+
+func TestWriteParamsXOR(t *testing.T) {
+	client, requests := newRecordingClient(t, testResponse(nsdp.WriteResponse, 0))
+	client.SetPasswordScheme(PasswordXOR)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	if err := client.WriteParams(mac, "password", ParamValue{Param: ParamLoopDetection, Value: []byte{0x01}}); err != nil {
+		t.Fatalf("WriteParams failed: %v", err)
+	}
+	assertWriteRequest(t, requests, ParamValue{Param: ParamPassword, Value: scramblePassword("password")})
+}
+
+func TestWriteParamsAuto(t *testing.T) {
+	client, requests := newRecordingClient(t,
+		testResponse(nsdp.ReadResponse, 0,
+			&nsdp.GenericTLV{Type: ParamPasswordEncryption, Length: 1, Value: []byte{0x00}},
+		),
+		testResponse(nsdp.WriteResponse, 0),
+	)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	if err := client.WriteParams(mac, "password", ParamValue{Param: ParamLoopDetection, Value: []byte{0x01}}); err != nil {
+		t.Fatalf("WriteParams failed: %v", err)
+	}
+
+	operation, tlvs := nextRequest(t, requests)
+	if operation != nsdp.ReadRequest || len(tlvs) != 3 || tlvs[1].Param != ParamPasswordEncryption || tlvs[2].Param != ParamPasswordSalt {
+		t.Errorf("Unexpected scheme request: operation %d, %+v", operation, tlvs)
+	}
+	assertWriteRequest(t, requests, ParamValue{Param: ParamPassword, Value: []byte("password")})
+}
+
+// assertWriteRequest checks that the next request is a write request
+// holding only the password TLV followed by the loop detection value.
+func assertWriteRequest(t *testing.T, requests <-chan []byte, password ParamValue) {
+	t.Helper()
+	operation, tlvs := nextRequest(t, requests)
+	if operation != nsdp.WriteRequest {
+		t.Fatalf("Expected a write request, got operation %d", operation)
+	}
+	expected := []ParamValue{password, {Param: ParamLoopDetection, Value: []byte{0x01}}}
+	if !reflect.DeepEqual(tlvs, expected) {
+		t.Errorf("Expected TLVs %+v, got %+v", expected, tlvs)
+	}
+}
+
+func TestWriteParamsError(t *testing.T) {
+	msg := nsdp.NewMessage(nsdp.WriteResponse)
+	msg.Header.Result = nsdp.OperationResult(WriteErrInvalidValue) << 8
+	msg.Header.Unknown1 = uint32(ParamLoopDetection) << 16
+	client := newTestClient(t, hex.EncodeToString(msg.Marshal()))
+	client.SetPasswordScheme(PasswordPlain)
+	mac, _ := net.ParseMAC("6c:b0:ce:1c:83:94")
+
+	err := client.WriteParams(mac, "password", ParamValue{Param: ParamLoopDetection, Value: []byte{0x07}})
+	var writeErr *WriteError
+	if !errors.As(err, &writeErr) {
+		t.Fatalf("Expected a WriteError, got %v", err)
+	}
+	if writeErr.Code != WriteErrInvalidValue || writeErr.Param != ParamLoopDetection {
+		t.Errorf("Unexpected error details: %+v", writeErr)
+	}
+	if got := writeErr.Error(); got != "device 6c:b0:ce:1c:83:94 rejected Loop Detection: invalid value" {
+		t.Errorf("Unexpected error message: %q", got)
+	}
+}
@@ -0,0 +1,223 @@
+// Package tftp implements a minimal read-only TFTP server (RFC 1350) that
+// serves a single file, as used to push firmware images to switches.
+package tftp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TFTP opcodes
+const (
+	opRRQ   = 1
+	opWRQ   = 2
+	opData  = 3
+	opAck   = 4
+	opError = 5
+)
+
+// TFTP error codes
+const (
+	errNotDefined   = 0
+	errFileNotFound = 1
+	errIllegalOp    = 4
+)
+
+const blockSize = 512
+
+// Server serves one file to TFTP read requests in octet mode. Requests for
+// other files are refused.
+type Server struct {
+	// Progress, if set, is called after each acknowledged block with the
+	// number of bytes transferred and the file size.
+	Progress func(sent, total int)
+	// Timeout is the time to wait for an acknowledgement before a block is
+	// sent again.
+	Timeout time.Duration
+	// Retries is the number of times a block is sent again before the
+	// transfer is aborted.
+	Retries int
+
+	conn net.PacketConn
+	name string
+	data []byte
+
+	closeOnce sync.Once
+}
+
+// Listen creates a server on addr (e.g. "192.168.0.10:69") serving data
+// under the given file name.
+func Listen(addr, name string, data []byte) (*Server, error) {
+	conn, err := net.ListenPacket("udp4", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		Timeout: 3 * time.Second,
+		Retries: 5,
+		conn:    conn,
+		name:    name,
+		data:    data,
+	}, nil
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+// Close stops the server. A running Serve returns an error.
+func (s *Server) Close() error {
+	var err error
+	s.closeOnce.Do(func() { err = s.conn.Close() })
+	return err
+}
+
+// Serve answers requests until the file has been transferred completely
+// once, and returns nil then. It returns an error if the server is closed or
+// a started transfer fails.
+func (s *Server) Serve() error {
+	buf := make([]byte, 1500)
+	for {
+		n, peer, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		file, err := parseRequest(buf[:n])
+		if err != nil {
+			s.sendError(s.conn, peer, errIllegalOp, err.Error())
+			continue
+		}
+		if !s.matches(file) {
+			s.sendError(s.conn, peer, errFileNotFound, fmt.Sprintf("file %q not found", file))
+			continue
+		}
+		return s.transfer(peer)
+	}
+}
+
+// matches reports whether a requested file name refers to the served file.
+// Clients may prefix the name with a directory.
+func (s *Server) matches(file string) bool {
+	return file == s.name || path.Base(strings.ReplaceAll(file, "\\", "/")) == s.name
+}
+
+// transfer sends the file to peer from a new transfer port, one block at a
+// time, each after the previous one was acknowledged.
+func (s *Server) transfer(peer net.Addr) error {
+	host, _, err := net.SplitHostPort(s.conn.LocalAddr().String())
+	if err != nil {
+		return err
+	}
+	conn, err := net.ListenPacket("udp4", net.JoinHostPort(host, "0"))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ack := make([]byte, 1500)
+	for block := 1; ; block++ {
+		start := (block - 1) * blockSize
+		end := start + blockSize
+		if end > len(s.data) {
+			end = len(s.data)
+		}
+		packet := make([]byte, 4, 4+end-start)
+		binary.BigEndian.PutUint16(packet[0:], opData)
+		binary.BigEndian.PutUint16(packet[2:], uint16(block)) // Wraps around for large files
+		packet = append(packet, s.data[start:end]...)
+
+		if err := s.sendBlock(conn, peer, packet, uint16(block), ack); err != nil {
+			s.sendError(conn, peer, errNotDefined, err.Error())
+			return err
+		}
+		if s.Progress != nil {
+			s.Progress(end, len(s.data))
+		}
+		// A block shorter than the block size ends the transfer
+		if end-start < blockSize {
+			return nil
+		}
+	}
+}
+
+// sendBlock sends a data packet until the peer acknowledges it.
+func (s *Server) sendBlock(conn net.PacketConn, peer net.Addr, packet []byte, block uint16, buf []byte) error {
+	for attempt := 0; attempt <= s.Retries; attempt++ {
+		if _, err := conn.WriteTo(packet, peer); err != nil {
+			return err
+		}
+		deadline := time.Now().Add(s.Timeout)
+		for {
+			conn.SetReadDeadline(deadline)
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					break
+				}
+				return err
+			}
+			if from.String() != peer.String() || n < 4 {
+				continue
+			}
+			switch binary.BigEndian.Uint16(buf[0:]) {
+			case opAck:
+				if binary.BigEndian.Uint16(buf[2:]) == block {
+					return nil
+				}
+				// Duplicate acknowledgement of an earlier block
+			case opError:
+				return fmt.Errorf("client aborted the transfer: %s", errorMessage(buf[4:n]))
+			}
+		}
+	}
+	return fmt.Errorf("no acknowledgement for block %d", block)
+}
+
+func (s *Server) sendError(conn net.PacketConn, peer net.Addr, code uint16, message string) {
+	packet := make([]byte, 4, 5+len(message))
+	binary.BigEndian.PutUint16(packet[0:], opError)
+	binary.BigEndian.PutUint16(packet[2:], code)
+	packet = append(packet, message...)
+	packet = append(packet, 0)
+	conn.WriteTo(packet, peer)
+}
+
+// parseRequest parses a read request and returns the requested file name.
+// Only the octet mode is supported; options (RFC 2347) are ignored, which
+// makes clients fall back to the defaults.
+func parseRequest(packet []byte) (string, error) {
+	if len(packet) < 4 {
+		return "", fmt.Errorf("short packet")
+	}
+	switch binary.BigEndian.Uint16(packet) {
+	case opRRQ:
+	case opWRQ:
+		return "", fmt.Errorf("write requests are not supported")
+	default:
+		return "", fmt.Errorf("unexpected opcode %d", binary.BigEndian.Uint16(packet))
+	}
+	fields := bytes.Split(packet[2:], []byte{0})
+	if len(fields) < 3 {
+		return "", fmt.Errorf("malformed read request")
+	}
+	if mode := strings.ToLower(string(fields[1])); mode != "octet" {
+		return "", fmt.Errorf("unsupported transfer mode %q", mode)
+	}
+	return string(fields[0]), nil
+}
+
+func errorMessage(value []byte) string {
+	if i := bytes.IndexByte(value, 0); i >= 0 {
+		value = value[:i]
+	}
+	return string(value)
+}
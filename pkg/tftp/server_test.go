@@ -0,0 +1,146 @@
+package tftp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func startServer(t *testing.T, data []byte) (*Server, chan error) {
+	t.Helper()
+	server, err := Listen("127.0.0.1:0", "image.bin", data)
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	server.Timeout = 100 * time.Millisecond
+	t.Cleanup(func() { server.Close() })
+
+	done := make(chan error, 1)
+	go func() { done <- server.Serve() }()
+	return server, done
+}
+
+func readRequest(file string) []byte {
+	packet := []byte{0, opRRQ}
+	packet = append(packet, file...)
+	packet = append(packet, 0)
+	packet = append(packet, "octet"...)
+	return append(packet, 0)
+}
+
+func ack(block uint16) []byte {
+	packet := make([]byte, 4)
+	binary.BigEndian.PutUint16(packet[0:], opAck)
+	binary.BigEndian.PutUint16(packet[2:], block)
+	return packet
+}
+
+// download reads the file from the server, skipping the acknowledgement of
+// the blocks in drop once to make the server send them again.
+func download(t *testing.T, server *Server, file string, drop map[uint16]bool) ([]byte, error) {
+	t.Helper()
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket failed: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.WriteTo(readRequest(file), server.Addr()); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	var data []byte
+	buf := make([]byte, 1500)
+	expected := uint16(1)
+	for {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			return nil, err
+		}
+		if binary.BigEndian.Uint16(buf) == opError {
+			return nil, errors.New(errorMessage(buf[4:n]))
+		}
+		block := binary.BigEndian.Uint16(buf[2:])
+		if block == expected {
+			data = append(data, buf[4:n]...)
+			expected++
+		}
+		if drop[block] {
+			delete(drop, block)
+			continue
+		}
+		conn.WriteTo(ack(block), from)
+		if block == expected-1 && n-4 < blockSize {
+			return data, nil
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 100) // 1600 bytes: 3 full blocks and 64 bytes
+	server, done := startServer(t, data)
+
+	var progress []int
+	server.Progress = func(sent, total int) {
+		if total != len(data) {
+			t.Errorf("Unexpected total: %d", total)
+		}
+		progress = append(progress, sent)
+	}
+
+	received, err := download(t, server, "/firmware/image.bin", map[uint16]bool{2: true})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if !bytes.Equal(received, data) {
+		t.Errorf("Received %d bytes, expected %d", len(received), len(data))
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Serve failed: %v", err)
+	}
+	if len(progress) != 4 || progress[3] != len(data) {
+		t.Errorf("Unexpected progress: %v", progress)
+	}
+}
+
+func TestServeExactMultiple(t *testing.T) {
+	data := bytes.Repeat([]byte{0xaa}, 2*blockSize)
+	server, done := startServer(t, data)
+
+	received, err := download(t, server, "image.bin", nil)
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if !bytes.Equal(received, data) {
+		t.Errorf("Received %d bytes, expected %d", len(received), len(data))
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Serve failed: %v", err)
+	}
+}
+
+func TestServeUnknownFile(t *testing.T) {
+	server, _ := startServer(t, []byte("data"))
+
+	if _, err := download(t, server, "other.bin", nil); err == nil {
+		t.Error("Expected error for unknown file")
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	if file, err := parseRequest(readRequest("image.bin")); err != nil || file != "image.bin" {
+		t.Errorf("Unexpected result: %q (%v)", file, err)
+	}
+	for _, packet := range [][]byte{
+		{0, opWRQ, 'a', 0, 'o', 'c', 't', 'e', 't', 0},
+		{0, opRRQ, 'a', 0, 'n', 'e', 't', 'a', 's', 'c', 'i', 'i', 0},
+		{0, opRRQ},
+	} {
+		if _, err := parseRequest(packet); err == nil {
+			t.Errorf("Expected error for %v", packet)
+		}
+	}
+}
@@ -18,7 +18,7 @@ echo "Output will be saved to: $OUTPUT_FILE"
 echo ""
 
 # Build if needed
-if [ ! -f "./nsdp_discovery" ]; then
+if [ ! -f "./nsdpctl" ]; then
     echo "Building discovery tool..."
     ./build_discovery.sh
 fi
@@ -54,7 +54,7 @@ echo ""
 # Test each known TLV individually for quick results
 for tlv in "${KNOWN_TLVS[@]}"; do
     echo "Testing TLV 0x$tlv..."
-    ./nsdp_discovery -i "$INTERFACE" -start "$tlv" -end "$tlv" -o "temp_${tlv}.txt" -v
+    ./nsdpctl -i "$INTERFACE" -v scan-tlv -start "$tlv" -end "$tlv" -file "temp_${tlv}.txt"
     echo ""
 done
 
@@ -0,0 +1,35 @@
+// message_tlv_generic.go
+//
+// Copyright (C) 2022 Holger de Carne
+//
+// This software may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+
+package nsdp
+
+import (
+	"fmt"
+)
+
+// TLV to exchange any parameter as raw bytes.
+//
+// Use it for parameters without a typed TLV. Unlike typed TLVs, a GenericTLV
+// keeps its value in read requests, for parameters the device needs an
+// argument for. Received elements of unknown type are decoded as GenericTLV.
+type GenericTLV struct {
+	Type   uint16 // Parameter type
+	Length uint16 // Length of Value (informational, the encoding uses len(Value))
+	Value  []byte // Raw value (empty to request the parameter)
+}
+
+func NewGenericTLV(tlvType uint16, value []byte) *GenericTLV {
+	return &GenericTLV{Type: tlvType, Length: uint16(len(value)), Value: value}
+}
+
+func (tlv *GenericTLV) encode() (Type, []byte) {
+	return Type(tlv.Type), tlv.Value
+}
+
+func (tlv *GenericTLV) String() string {
+	return fmt.Sprintf("GenericTLV(%04xh) %x", tlv.Type, tlv.Value)
+}
@@ -0,0 +1,126 @@
+// message_tlv_vlan_info.go
+//
+// Copyright (C) 2022 Holger de Carne
+//
+// This software may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+
+package nsdp
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// TLV to exchange the target device's 802.1Q VLAN membership.
+//
+// Add an empty VLANInfo TLV to a read request to receive a filled one for each of the device's VLANs.
+// The value holds the VLAN ID followed by two port bitmaps of equal width, the first listing the
+// member ports and the second the tagged ones.
+type VLANInfo struct {
+	VLANID        uint16  // The VLAN ID
+	TaggedPorts   []uint8 // Tagged member ports
+	UntaggedPorts []uint8 // Untagged member ports
+	BitmapWidth   int     // Width of each port bitmap in bytes (0: as small as possible)
+}
+
+func EmptyVLANInfo() *VLANInfo {
+	return &VLANInfo{}
+}
+
+func NewVLANInfo(vlanID uint16, tagged []uint8, untagged []uint8) *VLANInfo {
+	return &VLANInfo{
+		VLANID:        vlanID,
+		TaggedPorts:   tagged,
+		UntaggedPorts: untagged,
+	}
+}
+
+func unmarshalVLANInfo(value []byte) (*VLANInfo, error) {
+	len := len(value)
+	if len == 0 {
+		return EmptyVLANInfo(), nil
+	}
+	if len < 4 || len%2 != 0 {
+		return nil, fmt.Errorf("unexpected VLAN info length: %d", len)
+	}
+	width := (len - 2) / 2
+	members := decodePortBitmap(value[2 : 2+width])
+	tagged := decodePortBitmap(value[2+width:])
+	tlv := NewVLANInfo(binary.BigEndian.Uint16(value[0:2]), tagged, nil)
+	for _, port := range members {
+		if !containsPort(tagged, port) {
+			tlv.UntaggedPorts = append(tlv.UntaggedPorts, port)
+		}
+	}
+	tlv.BitmapWidth = width
+	return tlv, nil
+}
+
+func (tlv *VLANInfo) Type() Type {
+	return TypeGetVlanInfo
+}
+
+func (tlv *VLANInfo) Length() uint16 {
+	return uint16(2 + 2*tlv.bitmapWidth())
+}
+
+func (tlv *VLANInfo) Value() []byte {
+	width := tlv.bitmapWidth()
+	value := make([]byte, 2+2*width)
+	binary.BigEndian.PutUint16(value[0:2], tlv.VLANID)
+	encodePortBitmap(value[2:2+width], tlv.TaggedPorts)
+	encodePortBitmap(value[2:2+width], tlv.UntaggedPorts)
+	encodePortBitmap(value[2+width:], tlv.TaggedPorts)
+	return value
+}
+
+func (tlv *VLANInfo) String() string {
+	return fmt.Sprintf("VLANInfo(%04xh) VLAN%d Tagged: %v Untagged: %v", TypeGetVlanInfo, tlv.VLANID, tlv.TaggedPorts, tlv.UntaggedPorts)
+}
+
+func (tlv *VLANInfo) bitmapWidth() int {
+	width := tlv.BitmapWidth
+	for _, ports := range [][]uint8{tlv.TaggedPorts, tlv.UntaggedPorts} {
+		for _, port := range ports {
+			if needed := (int(port) + 7) / 8; needed > width {
+				width = needed
+			}
+		}
+	}
+	if width == 0 {
+		width = 1
+	}
+	return width
+}
+
+// decodePortBitmap returns the ports set in a bitmap, the most significant
+// bit of the first byte standing for port 1.
+func decodePortBitmap(bitmap []byte) []uint8 {
+	var ports []uint8
+	for i, b := range bitmap {
+		for bit := 0; bit < 8; bit++ {
+			if b&(0x80>>bit) != 0 {
+				ports = append(ports, uint8(i*8+bit+1))
+			}
+		}
+	}
+	return ports
+}
+
+func encodePortBitmap(bitmap []byte, ports []uint8) {
+	for _, port := range ports {
+		if port > 0 {
+			bitmap[(port-1)/8] |= 0x80 >> ((port - 1) % 8)
+		}
+	}
+}
+
+func containsPort(ports []uint8, port uint8) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}